@@ -275,7 +275,7 @@ func (r *Registry) updateAndGetControlsInTopology(pluginID string, topology *rep
 		node.LatestControls.ForEach(func(controlID string, ts time.Time, data report.NodeControlData) {
 			log.Debugf("plugins: got node control %s", controlID)
 			newControlID := ""
-			if _, found := topology.Controls[controlID]; !found {
+			if !topology.Controls.Has(controlID) {
 				log.Debugf("plugins: node control %s does not exist in topology controls", controlID)
 				newControlID = controlID
 			} else {
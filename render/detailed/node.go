@@ -43,22 +43,22 @@ func (*ControlInstance) UnmarshalJSON(b []byte) error {
 type wiredControlInstance struct {
 	ProbeID string `json:"probeId"`
 	NodeID  string `json:"nodeId"`
-	ID      string `json:"id"`
-	Human   string `json:"human"`
-	Icon    string `json:"icon"`
-	Rank    int    `json:"rank"`
+	controlFields
+	Cooldown int64 `json:"cooldown,omitempty"` // milliseconds, as in report.Control's encoding
 }
 
-// CodecEncodeSelf marshals this ControlInstance. It takes the basic Metric
-// rendering, then adds some row-specific fields.
+// controlFields has the fields of report.Control, but none of its methods,
+// so that they are flattened into wiredControlInstance.
+type controlFields report.Control
+
+// CodecEncodeSelf marshals this ControlInstance. It flattens the Control
+// into the instance, alongside the probe and node it belongs to.
 func (c *ControlInstance) CodecEncodeSelf(encoder *codec.Encoder) {
 	encoder.Encode(wiredControlInstance{
-		ProbeID: c.ProbeID,
-		NodeID:  c.NodeID,
-		ID:      c.Control.ID,
-		Human:   c.Control.Human,
-		Icon:    c.Control.Icon,
-		Rank:    c.Control.Rank,
+		ProbeID:       c.ProbeID,
+		NodeID:        c.NodeID,
+		controlFields: controlFields(c.Control),
+		Cooldown:      int64(c.Control.Cooldown / time.Millisecond),
 	})
 }
 
@@ -69,13 +69,9 @@ func (c *ControlInstance) CodecDecodeSelf(decoder *codec.Decoder) {
 	*c = ControlInstance{
 		ProbeID: in.ProbeID,
 		NodeID:  in.NodeID,
-		Control: report.Control{
-			ID:    in.ID,
-			Human: in.Human,
-			Icon:  in.Icon,
-			Rank:  in.Rank,
-		},
+		Control: report.Control(in.controlFields),
 	}
+	c.Control.Cooldown = time.Duration(in.Cooldown) * time.Millisecond
 }
 
 // MakeNode transforms a renderable node to a detailed node. It uses
@@ -107,7 +103,7 @@ func controlsFor(topology report.Topology, nodeID string) []ControlInstance {
 		if data.Dead {
 			return
 		}
-		if control, ok := topology.Controls[controlID]; ok {
+		if control, ok := topology.Controls.Get(controlID); ok {
 			result = append(result, ControlInstance{
 				ProbeID: probeID,
 				NodeID:  nodeID,
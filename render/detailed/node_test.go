@@ -1,9 +1,13 @@
 package detailed_test
 
 import (
+	"bytes"
 	"fmt"
+	stdreflect "reflect"
 	"testing"
+	"time"
 
+	"github.com/ugorji/go/codec"
 	"github.com/weaveworks/common/test"
 	"github.com/weaveworks/scope/probe/docker"
 	"github.com/weaveworks/scope/probe/host"
@@ -422,3 +426,79 @@ func TestMakeDetailedPodNode(t *testing.T) {
 		t.Errorf("%s", test.Diff(want, have))
 	}
 }
+
+func TestControlInstanceEncoding(t *testing.T) {
+	want := detailed.ControlInstance{
+		ProbeID: "probe",
+		NodeID:  "node",
+		Control: report.Control{
+			ID:             "scale",
+			Human:          "Scale",
+			Description:    "Change the number of replicas",
+			Icon:           "fa-arrows-v",
+			Rank:           1,
+			Confirm:        true,
+			ConfirmText:    "Are you sure?",
+			Disabled:       true,
+			DisabledReason: "rolling out",
+			Category:       "Lifecycle",
+			CategoryRank:   1,
+			Parameters: []report.ControlParameter{
+				{ID: "replicas", Label: "Replicas", Type: report.IntParameterType, Default: "1"},
+				{ID: "strategy", Label: "Strategy", Type: report.EnumParameterType, Options: []string{"recreate", "rolling"}},
+			},
+			Color:                  report.WarningControlColor,
+			Cooldown:               1500 * time.Millisecond,
+			RequiredRole:           "admin",
+			ParentID:               "deployment",
+			Deprecated:             true,
+			ReplacedBy:             "resize",
+			Async:                  true,
+			IdempotencyKeyTemplate: "{{nodeID}}-{{nonce}}",
+			VisibleWhen:            "state==running",
+			Type:                   report.LinkControlType,
+			URLTemplate:            "https://grafana/d/{{hostname}}",
+			TimeoutSeconds:         30,
+			AriaLabel:              "Scale the deployment",
+			Badge:                  "3 pending",
+		},
+	}
+	// Every field of Control must be set above, so that none is dropped.
+	control := stdreflect.ValueOf(want.Control)
+	for i := 0; i < control.NumField(); i++ {
+		if stdreflect.DeepEqual(control.Field(i).Interface(), stdreflect.Zero(control.Field(i).Type()).Interface()) {
+			t.Errorf("set Control.%s in this test", control.Type().Field(i).Name)
+		}
+	}
+
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(&want); err != nil {
+			t.Fatal(err)
+		}
+		var have detailed.ControlInstance
+		if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	minimal := detailed.ControlInstance{ProbeID: "probe", NodeID: "node", Control: report.Control{ID: "stop", Human: "Stop", Icon: "fa-stop"}}
+	if err := codec.NewEncoder(buf, &codec.JsonHandle{}).Encode(&minimal); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"description", "parameters", "cooldown", "badge", "ariaLabel"} {
+		if bytes.Contains(buf.Bytes(), []byte(field)) {
+			t.Errorf("empty %s should not be encoded: %s", field, buf.String())
+		}
+	}
+	if want, have := `{"human":"Stop","icon":"fa-stop","id":"stop","nodeId":"node","probeId":"probe","rank":0}`, buf.String(); want != have {
+		t.Errorf("want %s, have %s", want, have)
+	}
+}
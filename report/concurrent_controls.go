@@ -0,0 +1,52 @@
+package report
+
+import (
+	"sync"
+)
+
+// ConcurrentControls is a Controls which is safe to build up from many
+// goroutines, e.g. one per plugin. The zero value is ready to use.
+type ConcurrentControls struct {
+	mtx      sync.RWMutex
+	controls Controls
+}
+
+// NewConcurrentControls makes a ConcurrentControls holding a copy of cs.
+func NewConcurrentControls(cs Controls) *ConcurrentControls {
+	return &ConcurrentControls{controls: cs.Copy()}
+}
+
+// Add adds the given controls, replacing any with the same IDs.
+func (c *ConcurrentControls) Add(controls ...Control) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.controls == nil {
+		c.controls = MakeControlsWithCapacity(len(controls))
+	}
+	c.controls.AddControls(controls)
+}
+
+// Remove removes the controls with the given IDs.
+func (c *ConcurrentControls) Remove(ids ...string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.controls.RemoveControls(ids)
+}
+
+// Merge adds all of other, replacing any controls with the same IDs.
+func (c *ConcurrentControls) Merge(other Controls) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	if c.controls == nil {
+		c.controls = MakeControlsWithCapacity(len(other))
+	}
+	other.CopyInto(c.controls)
+}
+
+// Snapshot returns a copy of the controls, which is safe to encode or
+// modify while others keep using c.
+func (c *ConcurrentControls) Snapshot() Controls {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	return c.controls.Copy()
+}
@@ -0,0 +1,67 @@
+package report_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+func TestConcurrentControls(t *testing.T) {
+	var c report.ConcurrentControls
+	c.Add(report.Control{ID: "start"}, report.Control{ID: "stop"})
+	c.Merge(report.Controls{"exec": {ID: "exec"}, "stop": {ID: "stop", Human: "Stop"}})
+	c.Remove("start")
+
+	want := report.Controls{"stop": {ID: "stop", Human: "Stop"}, "exec": {ID: "exec"}}
+	snapshot := c.Snapshot()
+	if !reflect.DeepEqual(want, snapshot) {
+		t.Error(test.Diff(want, snapshot))
+	}
+
+	// The snapshot must not be affected by later changes, nor vice versa.
+	c.Add(report.Control{ID: "pause"})
+	snapshot.RemoveControl("exec")
+	want = report.Controls{"stop": {ID: "stop", Human: "Stop"}}
+	if !reflect.DeepEqual(want, snapshot) {
+		t.Error(test.Diff(want, snapshot))
+	}
+	if have := c.Snapshot(); !have.Has("exec") || !have.Has("pause") {
+		t.Errorf("unexpected controls %v", have)
+	}
+
+	original := report.Controls{"start": {ID: "start"}}
+	report.NewConcurrentControls(original).Add(report.Control{ID: "stop"})
+	if original.Has("stop") {
+		t.Error("NewConcurrentControls didn't copy its input")
+	}
+}
+
+// Run with -race.
+func TestConcurrentControlsConcurrent(t *testing.T) {
+	var (
+		c  = report.NewConcurrentControls(nil)
+		wg sync.WaitGroup
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				id := fmt.Sprintf("control-%d-%d", i, j)
+				c.Add(report.Control{ID: id})
+				c.Merge(report.Controls{id + "-merged": {ID: id + "-merged"}})
+				c.Snapshot().Validate()
+				c.Remove(id + "-merged")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if have := len(c.Snapshot()); have != 1000 {
+		t.Errorf("want 1000 controls, have %d", have)
+	}
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"github.com/ugorji/go/codec"
+)
+
+// ControlRegistry holds control definitions by ID, so that they can be sent
+// once rather than with every node, which need only carry the IDs of their
+// controls (see NodeControls).
+type ControlRegistry Controls
+
+// MakeControlRegistry makes an empty ControlRegistry.
+func MakeControlRegistry() ControlRegistry {
+	return ControlRegistry{}
+}
+
+// Register adds the definitions in cs to r, replacing any with the same ID.
+func (r ControlRegistry) Register(cs Controls) {
+	for _, c := range cs {
+		r[c.ID] = c
+	}
+}
+
+// Resolve returns the definitions of the given control IDs. IDs with no
+// definition in r are left out; see NodeControls.ValidateAgainst.
+func (r ControlRegistry) Resolve(ids StringSet) Controls {
+	result := MakeControlsWithCapacity(len(ids))
+	for _, id := range ids {
+		if c, ok := r[id]; ok {
+			result[id] = c
+		}
+	}
+	return result
+}
+
+// CodecEncodeSelf implements codec.Selfer
+func (r *ControlRegistry) CodecEncodeSelf(encoder *codec.Encoder) {
+	cs := Controls(*r)
+	cs.CodecEncodeSelf(encoder)
+}
+
+// CodecDecodeSelf implements codec.Selfer
+func (r *ControlRegistry) CodecDecodeSelf(decoder *codec.Decoder) {
+	var cs Controls
+	cs.CodecDecodeSelf(decoder)
+	*r = ControlRegistry(cs)
+}
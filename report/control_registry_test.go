@@ -0,0 +1,71 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/ugorji/go/codec"
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+func TestControlRegistry(t *testing.T) {
+	r := report.MakeControlRegistry()
+	r.Register(report.Controls{
+		"start": {ID: "start", Human: "Start", Icon: "fa-play"},
+		"stop":  {ID: "stop", Human: "Stop", Icon: "fa-stop"},
+	})
+	r.Register(report.Controls{
+		"stop": {ID: "stop", Human: "Stop now", Icon: "fa-stop"},
+	})
+
+	for _, testcase := range []struct {
+		ids  report.StringSet
+		want report.Controls
+	}{
+		{nil, report.Controls{}},
+		{report.MakeStringSet("start"), report.Controls{
+			"start": {ID: "start", Human: "Start", Icon: "fa-play"},
+		}},
+		{report.MakeStringSet("start", "stop"), report.Controls{
+			"start": {ID: "start", Human: "Start", Icon: "fa-play"},
+			"stop":  {ID: "stop", Human: "Stop now", Icon: "fa-stop"},
+		}},
+		{report.MakeStringSet("pause", "stop"), report.Controls{
+			"stop": {ID: "stop", Human: "Stop now", Icon: "fa-stop"},
+		}},
+	} {
+		if have := r.Resolve(testcase.ids); !reflect.DeepEqual(testcase.want, have) {
+			t.Errorf("Resolve(%v): %s", testcase.ids, test.Diff(testcase.want, have))
+		}
+	}
+}
+
+func TestControlRegistryEncoding(t *testing.T) {
+	want := report.MakeControlRegistry()
+	want.Register(report.Controls{
+		"start": {ID: "start", Human: "Start", Icon: "fa-play", Rank: 1},
+		"stop":  {ID: "stop", Human: "Stop", Icon: "fa-stop", Rank: 2},
+	})
+	nc := report.MakeNodeControls().Add("start")
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := []byte{}
+		if err := codec.NewEncoderBytes(&buf, h).Encode(&want); err != nil {
+			t.Fatal(err)
+		}
+		have := report.ControlRegistry{}
+		if err := codec.NewDecoderBytes(buf, h).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+		wantControls := report.Controls{"start": want["start"]}
+		if haveControls := have.Resolve(nc.Controls); !reflect.DeepEqual(wantControls, haveControls) {
+			t.Error(test.Diff(wantControls, haveControls))
+		}
+	}
+}
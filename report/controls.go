@@ -1,8 +1,19 @@
 package report
 
 import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/ugorji/go/codec"
 	"github.com/weaveworks/common/mtime"
 )
@@ -10,28 +21,591 @@ import (
 // Controls describe the control tags within the Nodes
 type Controls map[string]Control
 
+// MakeControls makes a new, empty Controls.
+func MakeControls() Controls {
+	return Controls{}
+}
+
+// MakeControlsWithCapacity makes a new, empty Controls with room for n
+// controls, which saves rehashing when the size is known up front.
+func MakeControlsWithCapacity(n int) Controls {
+	return make(Controls, n)
+}
+
 // A Control basically describes an RPC
 type Control struct {
-	ID    string `json:"id"`
-	Human string `json:"human"`
-	Icon  string `json:"icon"` // from https://fortawesome.github.io/Font-Awesome/cheatsheet/ please
-	Rank  int    `json:"rank"`
+	ID                     string             `json:"id"`
+	Human                  string             `json:"human"`
+	Description            string             `json:"description,omitempty"` // longer explanation, shown on hover
+	Icon                   string             `json:"icon"`                  // from https://fortawesome.github.io/Font-Awesome/cheatsheet/ please
+	Rank                   int                `json:"rank"`
+	Confirm                bool               `json:"confirm,omitempty"`                // ask the user before issuing the RPC
+	ConfirmText            string             `json:"confirmText,omitempty"`            // optional text for the confirmation dialog
+	Disabled               bool               `json:"disabled,omitempty"`               // shown greyed-out, cannot be issued
+	DisabledReason         string             `json:"disabledReason,omitempty"`         // why the control is disabled
+	Category               string             `json:"category,omitempty"`               // used to group controls in menus
+	CategoryRank           int                `json:"categoryRank,omitempty"`           // orders the categories; the same for all controls in one
+	Parameters             []ControlParameter `json:"parameters,omitempty"`             // inputs the UI asks for before issuing the RPC
+	Color                  string             `json:"color,omitempty"`                  // e.g. DangerControlColor, see EffectiveColor
+	Cooldown               time.Duration      `json:"cooldown,omitempty" codec:"-"`     // how long the UI disables the control after use
+	RequiredRole           string             `json:"requiredRole,omitempty"`           // only users with this role may see the control
+	ParentID               string             `json:"parentId,omitempty"`               // show the control in its parent's submenu
+	Deprecated             bool               `json:"deprecated,omitempty"`             // hidden by default, kept working for old clients
+	ReplacedBy             string             `json:"replacedBy,omitempty"`             // ID of the control to use instead of a deprecated one
+	Async                  bool               `json:"async,omitempty"`                  // long-running; the UI polls ControlProgress for the outcome
+	IdempotencyKeyTemplate string             `json:"idempotencyKeyTemplate,omitempty"` // how the UI derives a per-invocation key, e.g. "{{nodeID}}-{{nonce}}"
+	VisibleWhen            string             `json:"visibleWhen,omitempty"`            // e.g. "state==running", see Controls.Visible
+	Type                   string             `json:"type,omitempty"`                   // RPCControlType (the default) or LinkControlType
+	URLTemplate            string             `json:"urlTemplate,omitempty"`            // for link controls, filled in with node fields by the UI
+	TimeoutSeconds         int                `json:"timeoutSeconds,omitempty"`         // how long the app waits for the RPC; 0 means its default
+	AriaLabel              string             `json:"ariaLabel,omitempty"`              // fuller text for screen readers, see EffectiveAriaLabel
+	Badge                  string             `json:"badge,omitempty"`                  // short status shown on the button, e.g. "3 pending"
+}
+
+// wireControl is the intermediate type for encoding/decoding a Control.
+// Cooldown is sent as a number of milliseconds, for compactness.
+type wireControl struct {
+	controlFields
+	Cooldown int64 `json:"cooldown,omitempty"`
+	dummySelfer
+}
+
+// controlFields has the fields of Control, but none of its methods, so that
+// it can be embedded in wireControl.
+type controlFields Control
+
+// CodecEncodeSelf implements codec.Selfer
+func (c *Control) CodecEncodeSelf(encoder *codec.Encoder) {
+	encoder.Encode(wireControl{
+		controlFields: controlFields(*c),
+		Cooldown:      int64(c.Cooldown / time.Millisecond),
+	})
+}
+
+// CodecDecodeSelf implements codec.Selfer
+func (c *Control) CodecDecodeSelf(decoder *codec.Decoder) {
+	in := wireControl{}
+	in.CodecDecodeSelf(decoder)
+	*c = Control(in.controlFields)
+	c.Cooldown = time.Duration(in.Cooldown) * time.Millisecond
+}
+
+// MarshalJSON implements json.Marshaler. Like CodecEncodeSelf, it sends
+// Cooldown as a number of milliseconds.
+func (c Control) MarshalJSON() ([]byte, error) {
+	return json.Marshal(wireControl{
+		controlFields: controlFields(c),
+		Cooldown:      int64(c.Cooldown / time.Millisecond),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Like CodecDecodeSelf, it reads
+// Cooldown as a number of milliseconds.
+func (c *Control) UnmarshalJSON(b []byte) error {
+	in := wireControl{}
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	*c = Control(in.controlFields)
+	c.Cooldown = time.Duration(in.Cooldown) * time.Millisecond
+	return nil
+}
+
+// Types of Control. An empty Type is an RPC.
+const (
+	RPCControlType  = "rpc"  // issued to the probe as an RPC
+	LinkControlType = "link" // opens URLTemplate, e.g. a Grafana dashboard
+)
+
+// Colors a Control can be rendered in.
+const (
+	DefaultControlColor = "default"
+	PrimaryControlColor = "primary"
+	DangerControlColor  = "danger"
+	WarningControlColor = "warning"
+)
+
+var controlColors = map[string]struct{}{
+	DefaultControlColor: {},
+	PrimaryControlColor: {},
+	DangerControlColor:  {},
+	WarningControlColor: {},
+}
+
+// Types of ControlParameter.
+const (
+	StringParameterType = "string"
+	IntParameterType    = "int"
+	BoolParameterType   = "bool"
+	EnumParameterType   = "enum"
+)
+
+// A ControlParameter describes an input to a Control's RPC.
+type ControlParameter struct {
+	ID      string   `json:"id"`
+	Label   string   `json:"label"`
+	Type    string   `json:"type"`
+	Default string   `json:"default,omitempty"`
+	Options []string `json:"options,omitempty"` // only for EnumParameterType
+}
+
+func (p ControlParameter) equal(other ControlParameter) bool {
+	if p.ID != other.ID || p.Label != other.Label || p.Type != other.Type || p.Default != other.Default || len(p.Options) != len(other.Options) {
+		return false
+	}
+	for i := range p.Options {
+		if p.Options[i] != other.Options[i] {
+			return false
+		}
+	}
+	return true
 }
 
-// Merge merges other with cs, returning a fresh Controls.
+// Equal compares every field of c and other, including the order of
+// Parameters and their Options, which is significant. Don't use ==, which
+// can't compare Controls with slice fields.
+func (c Control) Equal(other Control) bool {
+	if c.ID != other.ID ||
+		c.Human != other.Human ||
+		c.Description != other.Description ||
+		c.Icon != other.Icon ||
+		c.Rank != other.Rank ||
+		c.Confirm != other.Confirm ||
+		c.ConfirmText != other.ConfirmText ||
+		c.Disabled != other.Disabled ||
+		c.DisabledReason != other.DisabledReason ||
+		c.Category != other.Category ||
+		c.CategoryRank != other.CategoryRank ||
+		c.Color != other.Color ||
+		c.Cooldown != other.Cooldown ||
+		c.RequiredRole != other.RequiredRole ||
+		c.ParentID != other.ParentID ||
+		c.Deprecated != other.Deprecated ||
+		c.ReplacedBy != other.ReplacedBy ||
+		c.Async != other.Async ||
+		c.IdempotencyKeyTemplate != other.IdempotencyKeyTemplate ||
+		c.VisibleWhen != other.VisibleWhen ||
+		c.Type != other.Type ||
+		c.URLTemplate != other.URLTemplate ||
+		c.TimeoutSeconds != other.TimeoutSeconds ||
+		c.AriaLabel != other.AriaLabel ||
+		c.Badge != other.Badge ||
+		len(c.Parameters) != len(other.Parameters) {
+		return false
+	}
+	for i := range c.Parameters {
+		if !c.Parameters[i].equal(other.Parameters[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// hash writes every field of c to h. Like Equal, it must be kept in step
+// with the fields of Control.
+func (c Control) hash(h hash.Hash64) {
+	hashStrings(h, c.ID, c.Human, c.Description, c.Icon)
+	hashInts(h, int64(c.Rank))
+	hashBools(h, c.Confirm)
+	hashStrings(h, c.ConfirmText)
+	hashBools(h, c.Disabled)
+	hashStrings(h, c.DisabledReason, c.Category)
+	hashInts(h, int64(c.CategoryRank))
+	hashInts(h, int64(len(c.Parameters)))
+	for _, p := range c.Parameters {
+		hashStrings(h, p.ID, p.Label, p.Type, p.Default)
+		hashInts(h, int64(len(p.Options)))
+		hashStrings(h, p.Options...)
+	}
+	hashStrings(h, c.Color)
+	hashInts(h, int64(c.Cooldown))
+	hashStrings(h, c.RequiredRole, c.ParentID)
+	hashBools(h, c.Deprecated)
+	hashStrings(h, c.ReplacedBy)
+	hashBools(h, c.Async)
+	hashStrings(h, c.IdempotencyKeyTemplate, c.VisibleWhen, c.Type, c.URLTemplate)
+	hashInts(h, int64(c.TimeoutSeconds))
+	hashStrings(h, c.AriaLabel, c.Badge)
+}
+
+// Strings are length-prefixed, so that e.g. ("ab", "c") and ("a", "bc")
+// hash differently.
+func hashStrings(h hash.Hash64, strs ...string) {
+	for _, s := range strs {
+		hashInts(h, int64(len(s)))
+		h.Write([]byte(s))
+	}
+}
+
+func hashInts(h hash.Hash64, is ...int64) {
+	var buf [8]byte
+	for _, i := range is {
+		binary.LittleEndian.PutUint64(buf[:], uint64(i))
+		h.Write(buf[:])
+	}
+}
+
+func hashBools(h hash.Hash64, bs ...bool) {
+	for _, b := range bs {
+		if b {
+			h.Write([]byte{1})
+		} else {
+			h.Write([]byte{0})
+		}
+	}
+}
+
+// EffectiveColor returns the color the control should be rendered in.
+func (c Control) EffectiveColor() string {
+	if c.Color == "" {
+		return DefaultControlColor
+	}
+	return c.Color
+}
+
+// EffectiveAriaLabel returns what screen readers should announce for the
+// control: its AriaLabel or, failing that, its Human text.
+func (c Control) EffectiveAriaLabel() string {
+	if c.AriaLabel == "" {
+		return c.Human
+	}
+	return c.AriaLabel
+}
+
+// HasParameters returns true if the control takes any input.
+func (c Control) HasParameters() bool {
+	return len(c.Parameters) > 0
+}
+
+// String renders the control as "<id> (<human>) rank=<n>".
+func (c Control) String() string {
+	return fmt.Sprintf("%s (%s) rank=%d", c.ID, c.Human, c.Rank)
+}
+
+// RequiresConfirmation returns true if the UI should ask the user to confirm
+// before issuing the control's RPC.
+func (c Control) RequiresConfirmation() bool {
+	return c.Confirm || c.ConfirmText != ""
+}
+
+// IsLink returns true if the control opens a URL rather than issuing an RPC.
+func (c Control) IsLink() bool {
+	return c.Type == LinkControlType
+}
+
+// IsActionable returns true if the control can currently be issued.
+func (c Control) IsActionable() bool {
+	return !c.Disabled
+}
+
+// Validate checks the control for missing or malformed fields.
+func (c Control) Validate() error {
+	switch {
+	case c.ID == "":
+		return fmt.Errorf("control has an empty ID")
+	case c.Human == "":
+		return fmt.Errorf("control %q has an empty human label", c.ID)
+	case c.Rank < 0:
+		return fmt.Errorf("control %q has a negative rank (%d)", c.ID, c.Rank)
+	case c.TimeoutSeconds < 0:
+		return fmt.Errorf("control %q has a negative timeout (%ds)", c.ID, c.TimeoutSeconds)
+	case c.Color != "" && !isValidColor(c.Color):
+		return fmt.Errorf("control %q has an unknown color %q", c.ID, c.Color)
+	case c.Type != "" && c.Type != RPCControlType && c.Type != LinkControlType:
+		return fmt.Errorf("control %q has an unknown type %q", c.ID, c.Type)
+	case c.IsLink() && c.URLTemplate == "":
+		return fmt.Errorf("link control %q has no URL template", c.ID)
+	case !c.IsLink() && c.URLTemplate != "":
+		return fmt.Errorf("rpc control %q has a URL template", c.ID)
+	case c.Icon != "" && !IsValidIcon(c.Icon):
+		return InvalidIconError{ID: c.ID, Icon: c.Icon}
+	}
+	return nil
+}
+
+// InvalidIconError is returned by Control.Validate when a control's icon is
+// not a known Font Awesome icon. It is only a warning: the control still
+// works, but is rendered without an icon.
+type InvalidIconError struct {
+	ID   string
+	Icon string
+}
+
+func (e InvalidIconError) Error() string {
+	return fmt.Sprintf("control %q has an unknown icon %q", e.ID, e.Icon)
+}
+
+func isValidColor(color string) bool {
+	_, ok := controlColors[color]
+	return ok
+}
+
+// IsValidIcon returns true if name is a known Font Awesome 4 icon, e.g.
+// "fa-trash-o", or the Font Awesome 5 name MigrateIcon gives one, e.g.
+// "fa-trash-alt".
+func IsValidIcon(name string) bool {
+	if _, ok := fontAwesomeIcons[name]; ok {
+		return true
+	}
+	_, ok := fontAwesome5Renamed[name]
+	return ok
+}
+
+// InvalidIcons returns the icon of each control in cs whose icon isn't a
+// known Font Awesome icon, keyed by control ID; see IsValidIcon. Controls
+// without an icon are fine.
+func (cs Controls) InvalidIcons() map[string]string {
+	result := map[string]string{}
+	for _, c := range cs {
+		if c.Icon != "" && !IsValidIcon(c.Icon) {
+			result[c.ID] = c.Icon
+		}
+	}
+	return result
+}
+
+// ValidationErrors is returned by Controls.Validate. It keeps each error
+// as it was, so that callers can tell warnings, like InvalidIconError, from
+// real problems.
+type ValidationErrors []error
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(errs))
+	for _, err := range errs {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("%d error(s): %s", len(errs), strings.Join(msgs, "; "))
+}
+
+// Fatal returns the errors in errs which aren't InvalidIconErrors, or nil
+// if there are none.
+func (errs ValidationErrors) Fatal() error {
+	var result ValidationErrors
+	for _, err := range errs {
+		if _, ok := err.(InvalidIconError); !ok {
+			result = append(result, err)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// Validate checks every control in cs, that each is keyed by its ID, and
+// that every ReplacedBy refers to a control in cs. Any errors are returned as
+// ValidationErrors.
+func (cs Controls) Validate() error {
+	var (
+		errs          ValidationErrors
+		categoryRanks = map[string]Control{}
+	)
+	for _, k := range cs.Keys() {
+		c := cs[k]
+		if first, ok := categoryRanks[c.Category]; !ok {
+			categoryRanks[c.Category] = c
+		} else if first.CategoryRank != c.CategoryRank {
+			errs = append(errs, fmt.Errorf("control %q gives category %q rank %d, but control %q gives it rank %d", c.ID, c.Category, c.CategoryRank, first.ID, first.CategoryRank))
+		}
+		if k != c.ID {
+			errs = append(errs, fmt.Errorf("control %q keyed by %q", c.ID, k))
+		}
+		if err := c.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+		if _, ok := cs[c.ReplacedBy]; c.ReplacedBy != "" && !ok {
+			errs = append(errs, fmt.Errorf("control %q replaced by unknown control %q", c.ID, c.ReplacedBy))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// DetectControlCollisions returns, for each control ID that sources define
+// differently, the distinct definitions in the order of sources. IDs which
+// all sources define the same way are not collisions.
+func DetectControlCollisions(sources ...Controls) map[string][]Control {
+	definitions := map[string][]Control{}
+	for _, cs := range sources {
+		for _, k := range cs.Keys() {
+			c, seen := cs[k], false
+			for _, d := range definitions[k] {
+				if d.Equal(c) {
+					seen = true
+					break
+				}
+			}
+			if !seen {
+				definitions[k] = append(definitions[k], c)
+			}
+		}
+	}
+	result := map[string][]Control{}
+	for k, defs := range definitions {
+		if len(defs) > 1 {
+			result[k] = defs
+		}
+	}
+	return result
+}
+
+// Merge merges other with cs, neither of which is modified. The result is
+// never nil. As an optimisation, if other is empty and cs isn't, the result
+// is cs itself rather than a copy: adding controls to it would add them to cs
+// too, so Copy it first unless cs is yours to modify. Otherwise the result
+// is a fresh map. Either way its Parameters are shared with the inputs;
+// Clone it to modify them.
 func (cs Controls) Merge(other Controls) Controls {
-	result := cs.Copy()
+	switch {
+	case len(cs) == 0 && len(other) == 0:
+		return MakeControls()
+	case len(other) == 0:
+		return cs
+	case len(cs) == 0:
+		return other.Copy()
+	}
+	result := MakeControlsWithCapacity(len(cs) + len(other))
+	for k, v := range cs {
+		result[k] = v
+	}
 	for k, v := range other {
 		result[k] = v
 	}
 	return result
 }
 
+// MergeMany merges all of others with cs, returning a fresh Controls. Later
+// controls win when IDs collide, as with Merge.
+func (cs Controls) MergeMany(others ...Controls) Controls {
+	size := len(cs)
+	for _, other := range others {
+		size += len(other)
+	}
+	result := MakeControlsWithCapacity(size)
+	for k, v := range cs {
+		result[k] = v
+	}
+	for _, other := range others {
+		for k, v := range other {
+			result[k] = v
+		}
+	}
+	return result
+}
+
 // Copy produces a copy of cs.
 func (cs Controls) Copy() Controls {
-	result := Controls{}
+	if len(cs) == 0 {
+		return MakeControls()
+	}
+	result := MakeControlsWithCapacity(len(cs))
+	cs.CopyInto(result)
+	return result
+}
+
+// Clone returns a deep copy of cs. Unlike Copy, the controls' Parameters
+// (and their Options) are copied too, so they can be modified freely.
+func (cs Controls) Clone() Controls {
+	result := MakeControlsWithCapacity(len(cs))
+	for k, c := range cs {
+		result[k] = c.clone()
+	}
+	return result
+}
+
+func (c Control) clone() Control {
+	if c.Parameters == nil {
+		return c
+	}
+	params := make([]ControlParameter, len(c.Parameters))
+	for i, p := range c.Parameters {
+		if p.Options != nil {
+			p.Options = append([]string(nil), p.Options...)
+		}
+		params[i] = p
+	}
+	c.Parameters = params
+	return c
+}
+
+// CopyInto copies the controls of cs into dst, so that callers can reuse a
+// map in tight loops. It does not clear dst first, and controls in cs replace
+// those with the same key in dst.
+func (cs Controls) CopyInto(dst Controls) {
 	for k, v := range cs {
-		result[k] = v
+		dst[k] = v
+	}
+}
+
+// Rename returns a copy of cs with the control keyed by oldID moved to
+// newID, and its ID updated to match. If cs already has a control keyed by
+// newID, that one is kept, as it comes from a newer probe. Renaming an
+// absent ID just copies cs.
+func (cs Controls) Rename(oldID, newID string) Controls {
+	result := cs.Copy()
+	c, ok := result[oldID]
+	if !ok || oldID == newID {
+		return result
+	}
+	delete(result, oldID)
+	if _, ok := result[newID]; !ok {
+		c.ID = newID
+		result[newID] = c
+	}
+	return result
+}
+
+// PrefixIDs returns a copy of cs with prefix prepended to every control's
+// key and ID, and to the ParentID and ReplacedBy references between them, so
+// that controls from different plugins can be merged without colliding. Use
+// StringSet.Map to prefix the IDs in NodeControls to match.
+func (cs Controls) PrefixIDs(prefix string) Controls {
+	result := MakeControlsWithCapacity(len(cs))
+	for k, c := range cs {
+		c.ID = prefix + c.ID
+		if c.ParentID != "" {
+			c.ParentID = prefix + c.ParentID
+		}
+		if c.ReplacedBy != "" {
+			c.ReplacedBy = prefix + c.ReplacedBy
+		}
+		result[prefix+k] = c
+	}
+	return result
+}
+
+// Update returns a copy of cs with every control for which match returns
+// true replaced by mutate(control), for applying overrides in one pass. If
+// mutate changes a control's ID it is re-keyed, replacing any control which
+// already had that ID.
+func (cs Controls) Update(match func(Control) bool, mutate func(Control) Control) Controls {
+	var (
+		result  = MakeControlsWithCapacity(len(cs))
+		updated = []Control{}
+	)
+	for _, k := range cs.Keys() {
+		if c := cs[k]; match(c) {
+			updated = append(updated, mutate(c))
+		} else {
+			result[k] = c
+		}
+	}
+	for _, c := range updated {
+		result[c.ID] = c
+	}
+	return result
+}
+
+// SetRank returns a copy of cs with the Rank of the control id set to rank,
+// so that the app can reorder controls without the probe changing. Setting
+// the rank of an absent ID just copies cs.
+func (cs Controls) SetRank(id string, rank int) Controls {
+	result := cs.Copy()
+	if c, ok := result[id]; ok {
+		c.Rank = rank
+		result[id] = c
 	}
 	return result
 }
@@ -41,13 +615,536 @@ func (cs Controls) AddControl(c Control) {
 	cs[c.ID] = c
 }
 
-// AddControls adds a collection of controls to cs.
+// AddControls adds a collection of controls to cs. A map can't be grown in
+// place, so when adding many controls to a new Controls, make it with
+// MakeControlsWithCapacity.
 func (cs Controls) AddControls(controls []Control) {
 	for _, c := range controls {
 		cs[c.ID] = c
 	}
 }
 
+// AddControlsFromMap adds all the controls in m to cs. Like AddControls, it
+// keys them by their IDs, whatever their keys in m, so that Validate holds.
+func (cs Controls) AddControlsFromMap(m map[string]Control) {
+	for _, c := range m {
+		cs[c.ID] = c
+	}
+}
+
+// Has returns true if cs contains a control with the given ID.
+func (cs Controls) Has(id string) bool {
+	_, ok := cs[id]
+	return ok
+}
+
+// Get returns the control with the given ID, and whether it was found.
+func (cs Controls) Get(id string) (Control, bool) {
+	c, ok := cs[id]
+	return c, ok
+}
+
+// RemoveControl removes the control with the given ID from cs.
+func (cs Controls) RemoveControl(id string) {
+	delete(cs, id)
+}
+
+// RemoveControls removes a collection of controls from cs.
+func (cs Controls) RemoveControls(ids []string) {
+	for _, id := range ids {
+		delete(cs, id)
+	}
+}
+
+// Filter returns a fresh Controls containing only the controls for which
+// keep returns true.
+func (cs Controls) Filter(keep func(Control) bool) Controls {
+	result := Controls{}
+	for k, c := range cs {
+		if keep(c) {
+			result[k] = c
+		}
+	}
+	return result
+}
+
+// FilterByRole returns the controls which require no role, or whose
+// RequiredRole is in roles.
+func (cs Controls) FilterByRole(roles StringSet) Controls {
+	return cs.Filter(func(c Control) bool {
+		return c.RequiredRole == "" || roles.Contains(c.RequiredRole)
+	})
+}
+
+// ActiveControls returns the controls which are not deprecated.
+func (cs Controls) ActiveControls() Controls {
+	return cs.Filter(func(c Control) bool { return !c.Deprecated })
+}
+
+// Visible returns the controls whose VisibleWhen condition holds for
+// nodeState. A condition is empty (always visible), "key==value" or
+// "key!=value"; controls with malformed conditions are kept, so that a typo
+// doesn't hide a control.
+func (cs Controls) Visible(nodeState map[string]string) Controls {
+	return cs.Filter(func(c Control) bool { return c.visibleIn(nodeState) })
+}
+
+func (c Control) visibleIn(nodeState map[string]string) bool {
+	for _, op := range []string{"==", "!="} {
+		parts := strings.SplitN(c.VisibleWhen, op, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if key == "" {
+			return true
+		}
+		state, ok := nodeState[key]
+		if op == "==" {
+			return ok && state == value
+		}
+		return !ok || state != value
+	}
+	return true
+}
+
+// WithoutCategory returns the controls which are not in category. An empty
+// category removes the uncategorized controls.
+func (cs Controls) WithoutCategory(category string) Controls {
+	return cs.Filter(func(c Control) bool { return c.Category != category })
+}
+
+// Compact returns the controls without the placeholders some plugins emit,
+// which have neither Human text nor an Icon and so can't be shown. Unlike
+// Validate, it just drops them.
+func (cs Controls) Compact() Controls {
+	return cs.Filter(func(c Control) bool { return c.Human != "" || c.Icon != "" })
+}
+
+// Intersect returns a fresh Controls with the controls of cs whose keys are
+// also in other.
+func (cs Controls) Intersect(other Controls) Controls {
+	result := Controls{}
+	for k, c := range cs {
+		if _, ok := other[k]; ok {
+			result[k] = c
+		}
+	}
+	return result
+}
+
+// Subtract returns a fresh Controls with the controls of cs whose keys are
+// not in other.
+func (cs Controls) Subtract(other Controls) Controls {
+	result := Controls{}
+	for k, c := range cs {
+		if _, ok := other[k]; !ok {
+			result[k] = c
+		}
+	}
+	return result
+}
+
+// Localize returns a copy of cs with each control's Human, Description and
+// AriaLabel passed through translate. Human is keyed by the control's ID;
+// Description and AriaLabel by the ID with ".description" and ".ariaLabel"
+// appended, so that a catalog keyed by ID can tell them apart. Probes ship
+// English; this is for the app to localize per request.
+func (cs Controls) Localize(translate func(id, human string) string) Controls {
+	result := MakeControlsWithCapacity(len(cs))
+	for k, c := range cs {
+		c.Human = translate(c.ID, c.Human)
+		if c.Description != "" {
+			c.Description = translate(c.ID+".description", c.Description)
+		}
+		if c.AriaLabel != "" {
+			c.AriaLabel = translate(c.ID+".ariaLabel", c.AriaLabel)
+		}
+		result[k] = c
+	}
+	return result
+}
+
+// Equal returns true if cs and other contain the same controls.
+func (cs Controls) Equal(other Controls) bool {
+	if len(cs) != len(other) {
+		return false
+	}
+	for k, c := range cs {
+		if o, ok := other[k]; !ok || !c.Equal(o) {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff compares cs to other, returning the controls only in other (added),
+// only in cs (removed), and in both but with different values (changed).
+// Changed controls take their values from other.
+func (cs Controls) Diff(other Controls) (added, removed, changed Controls) {
+	added, removed, changed = Controls{}, Controls{}, Controls{}
+	for k, c := range cs {
+		o, ok := other[k]
+		switch {
+		case !ok:
+			removed[k] = c
+		case !c.Equal(o):
+			changed[k] = o
+		}
+	}
+	for k, o := range other {
+		if _, ok := cs[k]; !ok {
+			added[k] = o
+		}
+	}
+	return added, removed, changed
+}
+
+// Hash returns an FNV hash of cs, which is the same for any two Controls
+// which are Equal, whatever their map iteration order.
+func (cs Controls) Hash() uint64 {
+	h := fnv.New64a()
+	for _, k := range cs.Keys() {
+		hashStrings(h, k)
+		cs[k].hash(h)
+	}
+	return h.Sum64()
+}
+
+// Keys returns the keys of cs, sorted. It never returns nil.
+func (cs Controls) Keys() []string {
+	keys := make([]string, 0, len(cs))
+	for k := range cs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// KeySet returns the keys of cs as a StringSet.
+func (cs Controls) KeySet() StringSet {
+	return StringSet(cs.Keys())
+}
+
+// Sorted returns the controls ordered by rank, breaking ties by ID.
+func (cs Controls) Sorted() []Control {
+	result := make([]Control, 0, len(cs))
+	for _, c := range cs {
+		result = append(result, c)
+	}
+	sort.Sort(controlsByRank(result))
+	return result
+}
+
+// Primary returns the control to show as the main action: the one with the
+// lowest rank, breaking ties by ID. It returns false if cs is empty.
+func (cs Controls) Primary() (Control, bool) {
+	var (
+		primary Control
+		found   bool
+	)
+	for _, c := range cs {
+		if !found || rankLess(c, primary) {
+			primary, found = c, true
+		}
+	}
+	return primary, found
+}
+
+// Secondary returns all the controls but the Primary one, in rank order.
+func (cs Controls) Secondary() []Control {
+	sorted := cs.Sorted()
+	if len(sorted) == 0 {
+		return sorted
+	}
+	return sorted[1:]
+}
+
+// ForEach calls f on each control in rank order, stopping as soon as f
+// returns false.
+func (cs Controls) ForEach(f func(Control) bool) {
+	for _, c := range cs.Sorted() {
+		if !f(c) {
+			return
+		}
+	}
+}
+
+// FindByHuman returns the controls whose Human contains query, ignoring
+// case, sorted by rank. An empty query matches every control.
+func (cs Controls) FindByHuman(query string) []Control {
+	query = strings.ToLower(query)
+	result := []Control{}
+	for _, c := range cs.Sorted() {
+		if strings.Contains(strings.ToLower(c.Human), query) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// String renders the controls sorted by rank, one per line.
+func (cs Controls) String() string {
+	buf := bytes.Buffer{}
+	for i, c := range cs.Sorted() {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(c.String())
+	}
+	return buf.String()
+}
+
+// ControlsGauge is an exported prometheus metric, counting the controls
+// in each topology by category. See Controls.ObserveMetrics.
+var ControlsGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "scope",
+		Subsystem: "report",
+		Name:      "controls",
+		Help:      "Number of controls exposed by a topology, by category.",
+	},
+	[]string{"topology", "category"},
+)
+
+// ObserveMetrics sets gauge (usually ControlsGauge) to the number of
+// controls in each category of cs, labelled with topology. Categories
+// observed for topology last time, but absent from cs, are removed from
+// gauge.
+func (cs Controls) ObserveMetrics(gauge *prometheus.GaugeVec, topology string) {
+	counts := map[string]int{}
+	for _, c := range cs {
+		counts[c.Category]++
+	}
+	for category, count := range counts {
+		gauge.WithLabelValues(topology, category).Set(float64(count))
+	}
+
+	observedCategories.Lock()
+	defer observedCategories.Unlock()
+	byTopology, ok := observedCategories.m[gauge]
+	if !ok {
+		byTopology = map[string]map[string]int{}
+		observedCategories.m[gauge] = byTopology
+	}
+	for category := range byTopology[topology] {
+		if _, ok := counts[category]; !ok {
+			gauge.DeleteLabelValues(topology, category)
+		}
+	}
+	byTopology[topology] = counts
+}
+
+// observedCategories records, for each gauge and topology, the categories
+// ObserveMetrics last set, so that it can remove those which disappear.
+var observedCategories = struct {
+	sync.Mutex
+	m map[*prometheus.GaugeVec]map[string]map[string]int
+}{m: map[*prometheus.GaugeVec]map[string]map[string]int{}}
+
+// ControlGroup is the controls of one category, see Controls.GroupByCategory.
+type ControlGroup struct {
+	Category string
+	Rank     int
+	Controls []Control
+}
+
+type controlGroupsByRank []ControlGroup
+
+func (g controlGroupsByRank) Len() int      { return len(g) }
+func (g controlGroupsByRank) Swap(i, j int) { g[i], g[j] = g[j], g[i] }
+func (g controlGroupsByRank) Less(i, j int) bool {
+	if g[i].Rank != g[j].Rank {
+		return g[i].Rank < g[j].Rank
+	}
+	return g[i].Category < g[j].Category
+}
+
+// GroupByCategory returns the controls bucketed by category, each bucket
+// sorted by rank. The buckets are sorted by CategoryRank, then by name;
+// uncategorized controls end up in the "" bucket. If the controls of a
+// category disagree on its rank (see Validate) the lowest is used.
+func (cs Controls) GroupByCategory() []ControlGroup {
+	groups := map[string]*ControlGroup{}
+	for _, c := range cs {
+		group, ok := groups[c.Category]
+		if !ok {
+			group = &ControlGroup{Category: c.Category, Rank: c.CategoryRank}
+			groups[c.Category] = group
+		} else if c.CategoryRank < group.Rank {
+			group.Rank = c.CategoryRank
+		}
+		group.Controls = append(group.Controls, c)
+	}
+	result := make([]ControlGroup, 0, len(groups))
+	for _, group := range groups {
+		sort.Sort(controlsByRank(group.Controls))
+		result = append(result, *group)
+	}
+	sort.Sort(controlGroupsByRank(result))
+	return result
+}
+
+// ControlNode is a Control with its child controls, see Controls.Tree.
+type ControlNode struct {
+	Control  Control
+	Children []ControlNode
+}
+
+// Tree assembles the controls into a forest using their ParentIDs, with each
+// level sorted by rank. Controls whose parent is missing are promoted to the
+// top level, as is the lowest ranked member of each cycle of ParentIDs.
+func (cs Controls) Tree() []ControlNode {
+	var (
+		sorted   = cs.Sorted()
+		roots    = []Control{}
+		children = map[string][]Control{}
+		visited  = map[string]struct{}{}
+		result   = []ControlNode{}
+	)
+	for _, c := range sorted {
+		if _, ok := cs[c.ParentID]; ok && c.ParentID != c.ID {
+			children[c.ParentID] = append(children[c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	var build func(Control) ControlNode
+	build = func(c Control) ControlNode {
+		visited[c.ID] = struct{}{}
+		node := ControlNode{Control: c}
+		for _, child := range children[c.ID] {
+			if _, ok := visited[child.ID]; !ok {
+				node.Children = append(node.Children, build(child))
+			}
+		}
+		return node
+	}
+	for _, c := range roots {
+		result = append(result, build(c))
+	}
+	// Anything not reached from a root is part of, or hangs off, a cycle.
+	// Break each cycle at its lowest ranked control, so that controls hanging
+	// off it stay attached.
+	for _, c := range sorted {
+		if _, ok := visited[c.ID]; !ok {
+			result = append(result, build(cs.cycleRoot(c)))
+		}
+	}
+	sort.Sort(controlNodesByRank(result))
+	return result
+}
+
+// cycleRoot follows the ParentIDs from c until one repeats, and returns the
+// lowest ranked control of the cycle found that way.
+func (cs Controls) cycleRoot(c Control) Control {
+	seen := map[string]struct{}{}
+	for {
+		if _, ok := seen[c.ID]; ok {
+			break
+		}
+		seen[c.ID] = struct{}{}
+		c = cs[c.ParentID]
+	}
+	root := c
+	for p := cs[c.ParentID]; p.ID != c.ID; p = cs[p.ParentID] {
+		if rankLess(p, root) {
+			root = p
+		}
+	}
+	return root
+}
+
+type controlNodesByRank []ControlNode
+
+func (ns controlNodesByRank) Len() int      { return len(ns) }
+func (ns controlNodesByRank) Swap(i, j int) { ns[i], ns[j] = ns[j], ns[i] }
+func (ns controlNodesByRank) Less(i, j int) bool {
+	return rankLess(ns[i].Control, ns[j].Control)
+}
+
+type controlsByRank []Control
+
+func (cs controlsByRank) Len() int           { return len(cs) }
+func (cs controlsByRank) Swap(i, j int)      { cs[i], cs[j] = cs[j], cs[i] }
+func (cs controlsByRank) Less(i, j int) bool { return rankLess(cs[i], cs[j]) }
+
+func rankLess(a, b Control) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.ID < b.ID
+}
+
+// EncodeHook and DecodeHook, if set, are called each time Controls or
+// NodeControls are encoded or decoded, with the number of controls and of
+// NodeControls processed, so that tracing can annotate spans without this
+// package importing it. The codec passes no context, so the hook must find
+// its span itself. Set them once, before any encoding; nil costs nothing.
+var (
+	EncodeHook func(controls, nodeControls int)
+	DecodeHook func(controls, nodeControls int)
+)
+
+// CodecEncodeSelf implements codec.Selfer. Controls are written sorted by
+// key, so that equal Controls always encode to identical bytes.
+func (cs *Controls) CodecEncodeSelf(encoder *codec.Encoder) {
+	z, r := codec.GenHelperEncoder(encoder)
+	if *cs == nil {
+		r.EncodeNil()
+		return
+	}
+	keys := cs.Keys()
+	if EncodeHook != nil {
+		EncodeHook(len(keys), 0)
+	}
+	r.EncodeMapStart(len(keys))
+	for _, k := range keys {
+		c := (*cs)[k]
+		z.EncSendContainerState(containerMapKey)
+		r.EncodeString(cUTF8, k)
+		z.EncSendContainerState(containerMapValue)
+		encoder.Encode(&c)
+	}
+	z.EncSendContainerState(containerMapEnd)
+}
+
+// CodecDecodeSelf implements codec.Selfer
+func (cs *Controls) CodecDecodeSelf(decoder *codec.Decoder) {
+	z, r := codec.GenHelperDecoder(decoder)
+	if r.TryDecodeAsNil() {
+		*cs = nil
+		return
+	}
+
+	length := r.ReadMapStart()
+	out := Controls{}
+	for i := 0; length < 0 || i < length; i++ {
+		if length < 0 && r.CheckBreak() {
+			break
+		}
+
+		var key string
+		z.DecSendContainerState(containerMapKey)
+		if !r.TryDecodeAsNil() {
+			key = r.DecodeString()
+		}
+
+		var c Control
+		z.DecSendContainerState(containerMapValue)
+		if !r.TryDecodeAsNil() {
+			decoder.Decode(&c)
+		}
+		out[key] = c
+	}
+	z.DecSendContainerState(containerMapEnd)
+	*cs = out
+	if DecodeHook != nil {
+		DecodeHook(len(out), 0)
+	}
+}
+
 // NodeControls represent the individual controls that are valid for a given
 // node at a given point in time.  It's immutable. A zero-value for Timestamp
 // indicated this NodeControls is 'not set'.
@@ -63,38 +1160,220 @@ func MakeNodeControls() NodeControls {
 	return emptyNodeControls
 }
 
+// IsSet returns true if this NodeControls has been populated, even if it has
+// no valid controls.
+func (nc NodeControls) IsSet() bool {
+	return !nc.Timestamp.IsZero()
+}
+
 // Merge returns the newest of the two NodeControls; it does not take the union
-// of the valid Controls.
+// of the valid Controls. If both have the same timestamp, the one with the
+// lexicographically greater Controls wins, so that Merge is commutative.
 func (nc NodeControls) Merge(other NodeControls) NodeControls {
-	if nc.Timestamp.Before(other.Timestamp) {
+	switch {
+	case nc.Timestamp.Before(other.Timestamp):
+		return other
+	case other.Timestamp.Before(nc.Timestamp):
+		return nc
+	case nc.Controls.less(other.Controls):
 		return other
 	}
 	return nc
 }
 
+// MergeStats describes the outcome of NodeControls.MergeWithStats.
+type MergeStats struct {
+	ReceiverWon    bool // otherwise, the argument won
+	SameTimestamps bool // the winner was picked by comparing Controls
+	Dropped        int  // control IDs of the loser which the winner lacks
+}
+
+// MergeWithStats is Merge, but also reports which side won and how many
+// control IDs were lost, to help find where MergeUnion is needed instead.
+func (nc NodeControls) MergeWithStats(other NodeControls) (NodeControls, MergeStats) {
+	stats := MergeStats{SameTimestamps: nc.Timestamp.Equal(other.Timestamp)}
+	winner, loser := other, nc
+	switch {
+	case nc.Timestamp.Before(other.Timestamp):
+	case other.Timestamp.Before(nc.Timestamp), !nc.Controls.less(other.Controls):
+		winner, loser = nc, other
+		stats.ReceiverWon = true
+	}
+	stats.Dropped = len(loser.Controls.Difference(winner.Controls))
+	return winner, stats
+}
+
+// MergeUnion returns a NodeControls with the newest of the two timestamps and
+// the union of the valid Controls.
+func (nc NodeControls) MergeUnion(other NodeControls) NodeControls {
+	timestamp := nc.Timestamp
+	if timestamp.Before(other.Timestamp) {
+		timestamp = other.Timestamp
+	}
+	return NodeControls{
+		Timestamp: timestamp,
+		Controls:  nc.Controls.Merge(other.Controls),
+	}
+}
+
 // Add the new control IDs to this NodeControls, producing a fresh NodeControls.
 func (nc NodeControls) Add(ids ...string) NodeControls {
+	return nc.WithTimestamp(mtime.Now(), ids...)
+}
+
+// WithTimestamp adds the new control IDs to this NodeControls, producing a
+// fresh NodeControls with the given timestamp.
+func (nc NodeControls) WithTimestamp(t time.Time, ids ...string) NodeControls {
 	return NodeControls{
-		Timestamp: mtime.Now(),
+		Timestamp: t,
 		Controls:  nc.Controls.Add(ids...),
 	}
 }
 
+// Remove the given control IDs from this NodeControls, producing a fresh
+// NodeControls.
+func (nc NodeControls) Remove(ids ...string) NodeControls {
+	return NodeControls{
+		Timestamp: mtime.Now(),
+		Controls:  nc.Controls.Remove(ids...),
+	}
+}
+
+// Toggle removes id from this NodeControls if it is valid, and adds it
+// otherwise, producing a fresh NodeControls.
+func (nc NodeControls) Toggle(id string) NodeControls {
+	if nc.Contains(id) {
+		return nc.Remove(id)
+	}
+	return nc.Add(id)
+}
+
+// Diff returns the controls valid in nc but not in previous, and those valid
+// in previous but not in nc. If previous is unset, all of nc's controls are
+// added.
+func (nc NodeControls) Diff(previous NodeControls) (added, removed StringSet) {
+	if !previous.IsSet() {
+		return nc.Controls, nil
+	}
+	return nc.Controls.Difference(previous.Controls), previous.Controls.Difference(nc.Controls)
+}
+
+// ValidateAgainst returns the IDs of nc's valid controls which have no
+// definition in available; these would show up as dead buttons.
+func (nc NodeControls) ValidateAgainst(available Controls) (unknown StringSet) {
+	return nc.Controls.Difference(available.KeySet())
+}
+
+// Contains returns true if the given control ID is valid for the node.
+func (nc NodeControls) Contains(id string) bool {
+	return nc.Controls.Contains(id)
+}
+
+// Len returns the number of valid controls for the node.
+func (nc NodeControls) Len() int {
+	return len(nc.Controls)
+}
+
 // WireNodeControls is the intermediate type for encoding/decoding.
 // Only needed for backwards compatibility with probes
 // (time.Time is encoded in binary in MsgPack)
 type wireNodeControls struct {
-	Timestamp string    `json:"timestamp,omitempty"`
-	Controls  StringSet `json:"controls,omitempty"`
+	Timestamp string         `json:"timestamp,omitempty"`
+	Controls  wireControlIDs `json:"controls,omitempty"`
 	dummySelfer
 }
 
+// wireNodeControlsPool saves allocating a wireNodeControls for each of the
+// many NodeControls in a report.
+var wireNodeControlsPool = sync.Pool{
+	New: func() interface{} { return &wireNodeControls{} },
+}
+
+// wireControlIDs is the StringSet of a wireNodeControls. It decodes the IDs
+// through a controlIDInterner, as the same few control IDs are repeated for
+// every node in a report.
+type wireControlIDs StringSet
+
+// maxInternedControlIDs bounds a controlIDInterner, in case of a report with
+// many distinct control IDs.
+const maxInternedControlIDs = 1024
+
+// controlIDInterner returns one string for each distinct control ID decoded.
+type controlIDInterner map[string]string
+
+func (i controlIDInterner) intern(b []byte) string {
+	if s, ok := i[string(b)]; ok { // doesn't allocate
+		return s
+	}
+	s := string(b)
+	if len(i) < maxInternedControlIDs {
+		i[s] = s
+	}
+	return s
+}
+
+// controlIDInternerPool shares interners across decode calls, so that a
+// report's control IDs are allocated once rather than once per node.
+var controlIDInternerPool = sync.Pool{
+	New: func() interface{} { return controlIDInterner{} },
+}
+
+// CodecEncodeSelf implements codec.Selfer
+func (ids *wireControlIDs) CodecEncodeSelf(encoder *codec.Encoder) {
+	z, r := codec.GenHelperEncoder(encoder)
+	if *ids == nil {
+		r.EncodeNil()
+		return
+	}
+	r.EncodeArrayStart(len(*ids))
+	for _, id := range *ids {
+		z.EncSendContainerState(containerArrayElem)
+		r.EncodeString(cUTF8, id)
+	}
+	z.EncSendContainerState(containerArrayEnd)
+}
+
+// CodecDecodeSelf implements codec.Selfer
+func (ids *wireControlIDs) CodecDecodeSelf(decoder *codec.Decoder) {
+	z, r := codec.GenHelperDecoder(decoder)
+	if r.TryDecodeAsNil() {
+		*ids = nil
+		return
+	}
+
+	interner := controlIDInternerPool.Get().(controlIDInterner)
+	length := r.ReadArrayStart()
+	out := wireControlIDs{}
+	if length > 0 {
+		out = make(wireControlIDs, 0, z.DecInferLen(length, z.DecBasicHandle().MaxInitLen, 16))
+	}
+	for i := 0; length < 0 || i < length; i++ {
+		if length < 0 && r.CheckBreak() {
+			break
+		}
+		z.DecSendContainerState(containerArrayElem)
+		if r.TryDecodeAsNil() {
+			out = append(out, "")
+		} else {
+			out = append(out, interner.intern(r.DecodeStringAsBytes()))
+		}
+	}
+	z.DecSendContainerState(containerArrayEnd)
+	controlIDInternerPool.Put(interner)
+	*ids = out
+}
+
 // CodecEncodeSelf implements codec.Selfer
 func (nc *NodeControls) CodecEncodeSelf(encoder *codec.Encoder) {
-	encoder.Encode(wireNodeControls{
-		Timestamp: renderTime(nc.Timestamp),
-		Controls:  nc.Controls,
-	})
+	out := wireNodeControlsPool.Get().(*wireNodeControls)
+	out.Timestamp = renderTime(nc.Timestamp)
+	out.Controls = wireControlIDs(nc.Controls)
+	encoder.Encode(out)
+	*out = wireNodeControls{} // don't keep nc.Controls alive from the pool
+	wireNodeControlsPool.Put(out)
+	if EncodeHook != nil {
+		EncodeHook(0, 1)
+	}
 }
 
 // CodecDecodeSelf implements codec.Selfer
@@ -102,23 +1381,268 @@ func (nc *NodeControls) CodecDecodeSelf(decoder *codec.Decoder) {
 	in := wireNodeControls{}
 	in.CodecDecodeSelf(decoder)
 	*nc = NodeControls{
-		Timestamp: parseTime(in.Timestamp),
-		Controls:  in.Controls,
+		Timestamp: parseNodeControlsTimestamp(in.Timestamp),
+		Controls:  StringSet(in.Controls),
 	}
+	if DecodeHook != nil {
+		DecodeHook(0, 1)
+	}
+}
+
+// parseNodeControlsTimestamp parses a timestamp as written by renderTime, or
+// as written by older probes, which encoded the time.Time in binary.
+func parseNodeControlsTimestamp(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t
+	}
+	var t time.Time
+	if err := t.UnmarshalBinary([]byte(s)); err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// GobEncode implements gob.GobEncoder, so that reports can be cached with
+// encoding/gob. It writes the same fields as CodecEncodeSelf.
+func (nc NodeControls) GobEncode() ([]byte, error) {
+	buf := bytes.Buffer{}
+	err := gob.NewEncoder(&buf).Encode(wireNodeControls{
+		Timestamp: renderTime(nc.Timestamp),
+		Controls:  wireControlIDs(nc.Controls),
+	})
+	return buf.Bytes(), err
+}
+
+// GobDecode implements gob.GobDecoder
+func (nc *NodeControls) GobDecode(b []byte) error {
+	in := wireNodeControls{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&in); err != nil {
+		return err
+	}
+	*nc = NodeControls{
+		Timestamp: parseNodeControlsTimestamp(in.Timestamp),
+		Controls:  StringSet(in.Controls),
+	}
+	return nil
 }
 
-// MarshalJSON shouldn't be used, use CodecEncodeSelf instead
-func (NodeControls) MarshalJSON() ([]byte, error) {
-	panic("MarshalJSON shouldn't be used, use CodecEncodeSelf instead")
+// EncodeCBOR encodes nc as CBOR, for probes which have a CBOR library but no
+// msgpack one. The shape is the same as with the other handles.
+func (nc NodeControls) EncodeCBOR() ([]byte, error) {
+	buf := []byte{}
+	if err := codec.NewEncoderBytes(&buf, &codec.CborHandle{}).Encode(&nc); err != nil {
+		return nil, err
+	}
+	return buf, nil
 }
 
-// UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead
-func (*NodeControls) UnmarshalJSON(b []byte) error {
-	panic("UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead")
+// DecodeCBOR is the inverse of EncodeCBOR.
+func (nc *NodeControls) DecodeCBOR(b []byte) error {
+	return codec.NewDecoderBytes(b, &codec.CborHandle{}).Decode(nc)
+}
+
+// MarshalJSON implements json.Marshaler. Prefer CodecEncodeSelf; this
+// produces the same shape but is much slower.
+func (nc NodeControls) MarshalJSON() ([]byte, error) {
+	return json.Marshal(wireNodeControls{
+		Timestamp: renderTime(nc.Timestamp),
+		Controls:  wireControlIDs(nc.Controls),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Prefer CodecDecodeSelf; this
+// accepts the same shape but is much slower.
+func (nc *NodeControls) UnmarshalJSON(b []byte) error {
+	in := wireNodeControls{}
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	*nc = NodeControls{
+		Timestamp: parseNodeControlsTimestamp(in.Timestamp),
+		Controls:  StringSet(in.Controls),
+	}
+	return nil
 }
 
 // NodeControlData contains specific information about the control. It
 // is used as a Value field of LatestEntry in NodeControlDataLatestMap.
 type NodeControlData struct {
-	Dead bool `json:"dead"`
+	Dead        bool      `json:"dead"`
+	LastError   string    `json:"lastError,omitempty"`   // error from the last invocation, if it failed
+	LastInvoked time.Time `json:"lastInvoked,omitempty"` // when the control was last invoked
+	DeadSince   time.Time `json:"deadSince,omitempty"`   // when Dead was reported, if before LastInvoked; see Merge
+}
+
+// wireNodeControlData is the intermediate type for encoding/decoding a
+// NodeControlData. Times are rendered as strings, so that zero times are ""
+// and left out; omitempty does nothing for a time.Time.
+type wireNodeControlData struct {
+	Dead        bool   `json:"dead"`
+	LastError   string `json:"lastError,omitempty"`
+	LastInvoked string `json:"lastInvoked,omitempty"`
+	DeadSince   string `json:"deadSince,omitempty"`
+	dummySelfer
+}
+
+func (d *NodeControlData) toIntermediate() wireNodeControlData {
+	return wireNodeControlData{
+		Dead:        d.Dead,
+		LastError:   d.LastError,
+		LastInvoked: renderTime(d.LastInvoked),
+		DeadSince:   renderTime(d.DeadSince),
+	}
+}
+
+func (d *NodeControlData) fromIntermediate(in wireNodeControlData) {
+	*d = NodeControlData{
+		Dead:        in.Dead,
+		LastError:   in.LastError,
+		LastInvoked: parseTime(in.LastInvoked),
+		DeadSince:   parseTime(in.DeadSince),
+	}
+}
+
+// CodecEncodeSelf implements codec.Selfer
+func (d *NodeControlData) CodecEncodeSelf(encoder *codec.Encoder) {
+	in := d.toIntermediate()
+	in.CodecEncodeSelf(encoder)
+}
+
+// CodecDecodeSelf implements codec.Selfer
+func (d *NodeControlData) CodecDecodeSelf(decoder *codec.Decoder) {
+	in := wireNodeControlData{}
+	in.CodecDecodeSelf(decoder)
+	d.fromIntermediate(in)
+}
+
+// MarshalJSON implements json.Marshaler. Prefer CodecEncodeSelf; this
+// produces the same shape.
+func (d NodeControlData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.toIntermediate())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Prefer CodecDecodeSelf; this
+// accepts the same shape.
+func (d *NodeControlData) UnmarshalJSON(b []byte) error {
+	in := wireNodeControlData{}
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	d.fromIntermediate(in)
+	return nil
+}
+
+// deadControlWindow is how long a report of a dead control outlives a newer
+// report saying otherwise, see NodeControlData.Merge.
+const deadControlWindow = 1 * time.Minute
+
+// Merge returns the more recently invoked of d and other. Dead is sticky: if
+// the control was reported dead within deadControlWindow of the newer one's
+// LastInvoked, the result is dead too, with DeadSince recording when, so that
+// probes racing each other don't resurrect a dead control. The window is
+// measured from that report, not from the merge, so Merge is associative as
+// well as commutative. On equal LastInvoked a report of the control dying
+// then, and then the greater LastError, wins.
+func (d NodeControlData) Merge(other NodeControlData) NodeControlData {
+	newer := d
+	if d.LastInvoked.Before(other.LastInvoked) || (d.LastInvoked.Equal(other.LastInvoked) && d.less(other)) {
+		newer = other
+	}
+	deadSince, dead := d.deadSince()
+	if t, ok := other.deadSince(); ok && (!dead || deadSince.Before(t)) {
+		deadSince, dead = t, true
+	}
+	newer.Dead = dead && newer.LastInvoked.Sub(deadSince) <= deadControlWindow
+	newer.DeadSince = time.Time{}
+	if newer.Dead && deadSince.Before(newer.LastInvoked) {
+		newer.DeadSince = deadSince
+	}
+	return newer
+}
+
+// deadSince returns when d was reported dead, if it is.
+func (d NodeControlData) deadSince() (time.Time, bool) {
+	switch {
+	case !d.Dead:
+		return time.Time{}, false
+	case d.DeadSince.IsZero():
+		return d.LastInvoked, true
+	}
+	return d.DeadSince, true
+}
+
+// diedAtLastInvocation is true if d was reported dead at its LastInvoked,
+// rather than kept dead by Merge. Unlike Dead, Merge doesn't change it.
+func (d NodeControlData) diedAtLastInvocation() bool {
+	t, ok := d.deadSince()
+	return ok && t.Equal(d.LastInvoked)
+}
+
+func (d NodeControlData) less(other NodeControlData) bool {
+	if a, b := d.diedAtLastInvocation(), other.diedAtLastInvocation(); a != b {
+		return b
+	}
+	return d.LastError < other.LastError
+}
+
+// ControlResult is the outcome of the last invocation of a control. It is
+// used as a Value field of LatestEntry in ControlResultLatestMap, keyed by
+// control ID.
+type ControlResult struct {
+	ControlID   string    `json:"controlId"`
+	Success     bool      `json:"success"`
+	Message     string    `json:"message,omitempty"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// Merge returns the more recently completed of r and other.
+func (r ControlResult) Merge(other ControlResult) ControlResult {
+	if r.CompletedAt.Before(other.CompletedAt) {
+		return other
+	}
+	return r
+}
+
+// ControlProgress is reported by probes while an Async control runs. It is
+// used as a Value field of LatestEntry in ControlProgressLatestMap, keyed by
+// control ID.
+type ControlProgress struct {
+	ControlID string `json:"controlId"`
+	Percent   int    `json:"percent"`
+	Message   string `json:"message,omitempty"`
+}
+
+// ControlAudit records who last invoked a control, and from where. It is
+// used as a Value field of LatestEntry in ControlAuditLatestMap, keyed by
+// control ID.
+type ControlAudit struct {
+	ControlID string    `json:"controlId"`
+	User      string    `json:"user"`
+	Source    string    `json:"source,omitempty"` // e.g. "ui", "api"
+	InvokedAt time.Time `json:"invokedAt"`
+}
+
+// Merge returns the more recently invoked of a and other.
+func (a ControlAudit) Merge(other ControlAudit) ControlAudit {
+	if a.InvokedAt.Before(other.InvokedAt) {
+		return other
+	}
+	return a
+}
+
+// PruneDead returns a copy of m without the entries that are dead and were
+// last updated before cutoff. Recently dead entries are kept, so that the
+// tombstone still has a chance to propagate.
+func (m NodeControlDataLatestMap) PruneDead(cutoff time.Time) NodeControlDataLatestMap {
+	out := MakeNodeControlDataLatestMap()
+	m.ForEach(func(k string, timestamp time.Time, v NodeControlData) {
+		if v.Dead && timestamp.Before(cutoff) {
+			return
+		}
+		out = out.Set(k, timestamp, v)
+	})
+	return out
 }
@@ -0,0 +1,44 @@
+package report
+
+import (
+	"github.com/ugorji/go/codec"
+)
+
+// controlsDelta is what EncodeDelta sends: the controls added or changed
+// since the baseline, and the IDs of those removed.
+type controlsDelta struct {
+	Set     Controls `json:"set,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	dummySelfer
+}
+
+// EncodeDelta encodes, as msgpack, only the controls which differ between
+// baseline and cs. Decode it with DecodeDelta and the same baseline.
+func (cs Controls) EncodeDelta(baseline Controls) ([]byte, error) {
+	added, removed, changed := baseline.Diff(cs)
+	delta := controlsDelta{Set: added}
+	changed.CopyInto(delta.Set)
+	if len(removed) > 0 {
+		delta.Removed = removed.Keys()
+	}
+	buf := []byte{}
+	if err := codec.NewEncoderBytes(&buf, &codec.MsgpackHandle{}).Encode(&delta); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// DecodeDelta reconstructs the Controls encoded by EncodeDelta from b and
+// baseline, which is not modified.
+func DecodeDelta(baseline Controls, b []byte) (Controls, error) {
+	delta := controlsDelta{}
+	if err := codec.NewDecoderBytes(b, &codec.MsgpackHandle{}).Decode(&delta); err != nil {
+		return nil, err
+	}
+	result := baseline.Copy()
+	for _, id := range delta.Removed {
+		delete(result, id)
+	}
+	delta.Set.CopyInto(result)
+	return result, nil
+}
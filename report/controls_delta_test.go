@@ -0,0 +1,78 @@
+package report_test
+
+import (
+	"testing"
+
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+func TestControlsDelta(t *testing.T) {
+	baseline := report.Controls{
+		"start":   {ID: "start", Human: "Start", Icon: "fa-play", Rank: 1},
+		"stop":    {ID: "stop", Human: "Stop", Icon: "fa-stop", Rank: 2},
+		"restart": {ID: "restart", Human: "Restart", Icon: "fa-repeat", Rank: 3},
+	}
+	for _, testcase := range []struct {
+		name    string
+		updated report.Controls
+	}{
+		{"unchanged", baseline.Copy()},
+		{"empty", report.Controls{}},
+		{"added", report.Controls{
+			"start":   baseline["start"],
+			"stop":    baseline["stop"],
+			"restart": baseline["restart"],
+			"pause":   {ID: "pause", Human: "Pause", Icon: "fa-pause", Rank: 4},
+		}},
+		{"removed", report.Controls{
+			"start": baseline["start"],
+		}},
+		{"changed", report.Controls{
+			"start":   baseline["start"],
+			"stop":    {ID: "stop", Human: "Stop now", Icon: "fa-stop", Rank: 2, Confirm: true},
+			"restart": baseline["restart"],
+		}},
+		{"all", report.Controls{
+			"stop":  {ID: "stop", Human: "Stop now", Icon: "fa-stop", Rank: 2},
+			"pause": {ID: "pause", Human: "Pause", Icon: "fa-pause", Rank: 4},
+		}},
+	} {
+		original := baseline.Copy()
+		b, err := testcase.updated.EncodeDelta(baseline)
+		if err != nil {
+			t.Fatalf("%s: %v", testcase.name, err)
+		}
+		have, err := report.DecodeDelta(baseline, b)
+		if err != nil {
+			t.Fatalf("%s: %v", testcase.name, err)
+		}
+		if !reflect.DeepEqual(testcase.updated, have) {
+			t.Errorf("%s: %s", testcase.name, test.Diff(testcase.updated, have))
+		}
+		if !reflect.DeepEqual(original, baseline) {
+			t.Errorf("%s: modified the baseline", testcase.name)
+		}
+	}
+}
+
+func TestControlsDeltaIsSmaller(t *testing.T) {
+	baseline := report.Controls{}
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		baseline[id] = report.Control{ID: id, Human: "Control " + id, Description: "Does " + id + " to the node", Icon: "fa-" + id}
+	}
+	updated := baseline.Copy()
+	delete(updated, "a")
+	full, err := updated.EncodeDelta(report.Controls{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delta, err := updated.EncodeDelta(baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(delta) >= len(full) {
+		t.Errorf("expected the delta (%d bytes) to be smaller than the full encoding (%d bytes)", len(delta), len(full))
+	}
+}
@@ -0,0 +1,49 @@
+//go:build go1.18
+// +build go1.18
+
+package report_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ugorji/go/codec"
+	"github.com/weaveworks/scope/report"
+)
+
+func FuzzNodeControlsDecode(f *testing.F) {
+	h := &codec.MsgpackHandle{}
+	for _, nc := range []report.NodeControls{
+		report.MakeNodeControls(),
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("foo")},
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("bar", "baz", "foo")},
+	} {
+		buf := []byte{}
+		if err := codec.NewEncoderBytes(&buf, h).Encode(&nc); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buf)
+		f.Add(buf[:len(buf)/2])
+		f.Add(buf[:len(buf)-1])
+	}
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var nc report.NodeControls
+		if err := codec.NewDecoderBytes(b, h).Decode(&nc); err != nil {
+			return
+		}
+		// Whatever was decoded must survive another round trip.
+		buf := []byte{}
+		if err := codec.NewEncoderBytes(&buf, h).Encode(&nc); err != nil {
+			t.Fatal(err)
+		}
+		var again report.NodeControls
+		if err := codec.NewDecoderBytes(buf, h).Decode(&again); err != nil {
+			t.Fatal(err)
+		}
+		if !nc.Timestamp.Equal(again.Timestamp) || !nc.Controls.Equal(again.Controls) {
+			t.Errorf("%v changed on re-encoding to %v", nc, again)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package report
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+
+	"github.com/weaveworks/scope/report/reportpb"
+)
+
+// MarshalProto encodes nc as a reportpb.NodeControls protobuf. This is an
+// alternative to CodecEncodeSelf, for probes not written in Go.
+func (nc NodeControls) MarshalProto() ([]byte, error) {
+	out := reportpb.NodeControls{Controls: nc.Controls}
+	if !nc.Timestamp.IsZero() {
+		ts, err := ptypes.TimestampProto(nc.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		out.Timestamp = ts
+	}
+	return proto.Marshal(&out)
+}
+
+// UnmarshalProto is the inverse of MarshalProto.
+func (nc *NodeControls) UnmarshalProto(b []byte) error {
+	in := reportpb.NodeControls{}
+	if err := proto.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	result := NodeControls{Controls: MakeStringSet(in.Controls...)}
+	if in.Timestamp != nil {
+		ts, err := ptypes.Timestamp(in.Timestamp)
+		if err != nil {
+			return err
+		}
+		result.Timestamp = ts
+	}
+	*nc = result
+	return nil
+}
@@ -0,0 +1,51 @@
+package report_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ugorji/go/codec"
+
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+func TestNodeControlsProto(t *testing.T) {
+	for _, nc := range []report.NodeControls{
+		{},
+		report.MakeNodeControls(),
+		report.MakeNodeControls().WithTimestamp(time.Date(2017, 3, 14, 15, 9, 26, 535897932, time.UTC), "bar", "foo"),
+		report.MakeNodeControls().WithTimestamp(time.Now()),
+	} {
+		b, err := nc.MarshalProto()
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromProto := report.NodeControls{}
+		if err := fromProto.UnmarshalProto(b); err != nil {
+			t.Fatal(err)
+		}
+
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, &codec.MsgpackHandle{}).Encode(nc); err != nil {
+			t.Fatal(err)
+		}
+		fromCodec := report.NodeControls{}
+		if err := codec.NewDecoder(buf, &codec.MsgpackHandle{}).Decode(&fromCodec); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(fromCodec, fromProto) {
+			t.Error(test.Diff(fromCodec, fromProto))
+		}
+	}
+}
+
+func TestNodeControlsProtoInvalid(t *testing.T) {
+	nc := report.NodeControls{}
+	if err := nc.UnmarshalProto([]byte{0xff}); err == nil {
+		t.Error("expected an error decoding garbage")
+	}
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ControlSchema returns a JSON Schema describing the JSON encoding of
+// Control, Controls and NodeControls. It is derived from the struct tags, so
+// it can't drift from the types.
+func ControlSchema() []byte {
+	schema := map[string]interface{}{
+		"$schema": "http://json-schema.org/draft-04/schema#",
+		"definitions": map[string]interface{}{
+			"Control":          structSchema(reflect.TypeOf(Control{})),
+			"ControlParameter": structSchema(reflect.TypeOf(ControlParameter{})),
+			"Controls": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"$ref": "#/definitions/Control"},
+			},
+			"NodeControls": structSchema(reflect.TypeOf(wireNodeControls{})),
+		},
+	}
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		panic(err) // only maps, slices and strings; can't fail
+	}
+	return b
+}
+
+func structSchema(t reflect.Type) map[string]interface{} {
+	var (
+		properties = map[string]interface{}{}
+		required   = []string{}
+	)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.PkgPath != "" || tag == "" || tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		properties[parts[0]] = typeSchema(field.Type)
+		if !(len(parts) > 1 && parts[1] == "omitempty") {
+			required = append(required, parts[0])
+		}
+	}
+	result := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		result["required"] = required
+	}
+	return result
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return map[string]interface{}{"type": "integer", "description": "milliseconds"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return map[string]interface{}{"$ref": "#/definitions/" + t.Name()}
+	}
+	panic("no JSON Schema for " + t.String())
+}
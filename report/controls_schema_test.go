@@ -0,0 +1,43 @@
+package report_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/weaveworks/scope/report"
+)
+
+func TestControlSchema(t *testing.T) {
+	var schema struct {
+		Definitions map[string]struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"definitions"`
+	}
+	if err := json.Unmarshal(report.ControlSchema(), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+
+	for _, name := range []string{"Control", "ControlParameter", "Controls", "NodeControls"} {
+		if _, ok := schema.Definitions[name]; !ok {
+			t.Errorf("schema has no definition for %s", name)
+		}
+	}
+
+	typ := reflect.TypeOf(report.Control{})
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+		if _, ok := schema.Definitions["Control"].Properties[name]; !ok {
+			t.Errorf("schema for Control is missing field %s (%q)", typ.Field(i).Name, name)
+		}
+	}
+	if cooldown, _ := schema.Definitions["Control"].Properties["cooldown"].(map[string]interface{}); cooldown["description"] != "milliseconds" {
+		t.Errorf("schema for Control should document cooldown as milliseconds, got %v", cooldown)
+	}
+	for _, name := range []string{"timestamp", "controls"} {
+		if _, ok := schema.Definitions["NodeControls"].Properties[name]; !ok {
+			t.Errorf("schema for NodeControls is missing %q", name)
+		}
+	}
+}
@@ -0,0 +1,2740 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	stdreflect "reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/ugorji/go/codec"
+
+	"github.com/weaveworks/common/mtime"
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+func TestControlsEncoding(t *testing.T) {
+	for _, want := range []report.Controls{
+		{
+			"foo": {ID: "foo", Human: "Foo", Icon: "fa-foo", Rank: 1},
+		},
+		{
+			"foo": {ID: "foo", Human: "Foo", Description: "Does foo to the node", Icon: "fa-foo", Rank: 1},
+			"bar": {ID: "bar", Human: "Bar", Icon: "fa-bar", Rank: 2},
+		},
+		{
+			"scale": {ID: "scale", Human: "Scale", Icon: "fa-arrows-v", Parameters: []report.ControlParameter{
+				{ID: "replicas", Label: "Replicas", Type: report.IntParameterType, Default: "1"},
+				{ID: "strategy", Label: "Strategy", Type: report.EnumParameterType, Options: []string{"recreate", "rolling"}},
+			}},
+		},
+		{
+			"restart": {ID: "restart", Human: "Restart", Icon: "fa-repeat", Cooldown: 1500 * time.Millisecond},
+		},
+		{
+			"delete": {ID: "delete", Human: "Delete", Icon: "fa-trash-o", RequiredRole: "admin"},
+		},
+		{
+			"rm":     {ID: "rm", Human: "Remove", Icon: "fa-trash-o", Deprecated: true, ReplacedBy: "delete"},
+			"delete": {ID: "delete", Human: "Delete", Icon: "fa-trash-o"},
+		},
+		{
+			"drain": {ID: "drain", Human: "Drain", Icon: "fa-sign-out", Async: true},
+		},
+		{
+			"delete": {ID: "delete", Human: "Delete", Icon: "fa-trash-o", IdempotencyKeyTemplate: "{{nodeID}}-{{nonce}}"},
+		},
+		{
+			"start": {ID: "start", Human: "Start", Icon: "fa-play", VisibleWhen: "state==stopped"},
+		},
+		{
+			"grafana": {ID: "grafana", Human: "Grafana", Icon: "fa-line-chart", Type: report.LinkControlType, URLTemplate: "https://grafana/d/{{hostname}}"},
+		},
+		{
+			"pull": {ID: "pull", Human: "Pull image", Icon: "fa-download", TimeoutSeconds: 300},
+		},
+		{
+			"logs": {ID: "logs", Human: "Logs", Icon: "fa-file-text-o", Category: "Diagnostics", CategoryRank: 2},
+		},
+		{
+			"stop": {ID: "stop", Human: "Stop", Icon: "fa-stop", AriaLabel: "Stop the container"},
+		},
+		{
+			"approve": {ID: "approve", Human: "Approve", Icon: "fa-check", Badge: "3 pending"},
+		},
+	} {
+		for _, h := range []codec.Handle{
+			codec.Handle(&codec.MsgpackHandle{}),
+			codec.Handle(&codec.JsonHandle{}),
+		} {
+			buf := &bytes.Buffer{}
+			if err := codec.NewEncoder(buf, h).Encode(want); err != nil {
+				t.Fatal(err)
+			}
+			have := report.Controls{}
+			if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(want, have) {
+				t.Error(test.Diff(want, have))
+			}
+		}
+	}
+}
+
+// encoding/json sorts map keys and escapes HTML just like the codec, so
+// Controls need no MarshalJSON of their own to match what the API sends.
+func TestControlsMarshalJSONMatchesCodec(t *testing.T) {
+	cs := report.Controls{
+		"restart": {ID: "restart", Human: "Restart", Icon: "fa-repeat", Rank: 1, Cooldown: 1500 * time.Millisecond, Confirm: true, ConfirmText: "Restart <nginx> & drop connections?"},
+		"scale": {ID: "scale", Human: "Scale", Icon: "fa-arrows-v", Parameters: []report.ControlParameter{
+			{ID: "replicas", Type: report.IntParameterType, Default: "1"},
+		}},
+	}
+	want := &bytes.Buffer{}
+	if err := codec.NewEncoder(want, &codec.JsonHandle{}).Encode(&cs); err != nil {
+		t.Fatal(err)
+	}
+	have, err := json.Marshal(cs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want.Bytes(), have) {
+		t.Errorf("expected encoding/json to match the codec:\n%s\n%s", want.Bytes(), have)
+	}
+}
+
+func TestControlsMarshalJSONIsStable(t *testing.T) {
+	a, b := report.Controls{}, report.Controls{}
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("control-%d", i)
+		a[id] = report.Control{ID: id, Human: id, Rank: i, Parameters: []report.ControlParameter{
+			{ID: "strategy", Type: report.EnumParameterType, Options: []string{"rolling", "recreate"}},
+		}}
+	}
+	for i := 19; i >= 0; i-- {
+		id := fmt.Sprintf("control-%d", i)
+		b[id] = a[id]
+	}
+
+	first, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cs := range []report.Controls{a, b} {
+		have, err := json.Marshal(cs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(first, have) {
+			t.Errorf("expected identical bytes:\n%s\n%s", first, have)
+		}
+	}
+
+	var decoded report.Controls
+	if err := json.Unmarshal(first, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, decoded) {
+		t.Error(test.Diff(a, decoded))
+	}
+	if have, err := json.Marshal(report.Controls(nil)); err != nil || string(have) != "null" {
+		t.Errorf("nil Controls: want null, have %s (%v)", have, err)
+	}
+}
+
+func TestControlsEncodingOmitsEmptyFields(t *testing.T) {
+	controls := report.Controls{
+		"foo": {ID: "foo", Human: "Foo", Icon: "fa-foo", Rank: 1},
+	}
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(controls); err != nil {
+			t.Fatal(err)
+		}
+		for _, field := range []string{"description", "cooldown", "idempotencyKeyTemplate", "timeoutSeconds", "categoryRank", "ariaLabel", "badge"} {
+			if bytes.Contains(buf.Bytes(), []byte(field)) {
+				t.Errorf("empty %s should not be encoded: %q", field, buf.String())
+			}
+		}
+	}
+}
+
+func TestDetectControlCollisions(t *testing.T) {
+	var (
+		restart     = report.Control{ID: "restart", Human: "Restart", Rank: 1}
+		restartSlow = report.Control{ID: "restart", Human: "Restart", Rank: 1, TimeoutSeconds: 300}
+		restartNow  = report.Control{ID: "restart", Human: "Restart now", Rank: 1}
+		stop        = report.Control{ID: "stop", Human: "Stop"}
+		logs        = report.Control{ID: "logs", Human: "Logs"}
+	)
+	for _, tc := range []struct {
+		name    string
+		sources []report.Controls
+		want    map[string][]report.Control
+	}{
+		{"none", nil, map[string][]report.Control{}},
+		{"disjoint", []report.Controls{{"restart": restart}, {"stop": stop}}, map[string][]report.Control{}},
+		{"identical", []report.Controls{
+			{"restart": restart, "stop": stop},
+			{"restart": restart},
+			{"restart": restart, "logs": logs},
+		}, map[string][]report.Control{}},
+		{"conflicting", []report.Controls{
+			{"restart": restart, "stop": stop},
+			{"restart": restartSlow, "stop": stop},
+			{"restart": restart},
+			{"restart": restartNow, "logs": logs},
+		}, map[string][]report.Control{
+			"restart": {restart, restartSlow, restartNow},
+		}},
+	} {
+		if have := report.DetectControlCollisions(tc.sources...); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%s: %s", tc.name, test.Diff(tc.want, have))
+		}
+	}
+}
+
+func TestControlsMergeCopy(t *testing.T) {
+	controls := report.Controls{}
+	controls.AddControl(report.Control{ID: "foo", Human: "Foo", Description: "Does foo", Icon: "fa-foo", Rank: 1})
+	controls.AddControls([]report.Control{{ID: "bar", Human: "Bar", Description: "Does bar", Icon: "fa-bar", Rank: 2}})
+
+	want := report.Controls{
+		"foo": {ID: "foo", Human: "Foo", Description: "Does foo", Icon: "fa-foo", Rank: 1},
+		"bar": {ID: "bar", Human: "Bar", Description: "Does bar", Icon: "fa-bar", Rank: 2},
+	}
+	if have := controls.Copy(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if have := (report.Controls{}).Merge(controls); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func TestControlsAddControlsFromMap(t *testing.T) {
+	var (
+		start   = report.Control{ID: "start", Human: "Start"}
+		stop    = report.Control{ID: "stop", Human: "Stop"}
+		stopNow = report.Control{ID: "stop", Human: "Stop now"}
+	)
+	for _, tc := range []struct {
+		name     string
+		controls report.Controls
+		m        map[string]report.Control
+		want     report.Controls
+	}{
+		{"normal", report.Controls{}, map[string]report.Control{"start": start, "stop": stop}, report.Controls{"start": start, "stop": stop}},
+		{"mismatched key", report.Controls{}, map[string]report.Control{"halt": stop}, report.Controls{"stop": stop}},
+		{"overwrite", report.Controls{"start": start, "stop": stop}, map[string]report.Control{"stop": stopNow}, report.Controls{"start": start, "stop": stopNow}},
+		{"nil", report.Controls{"start": start}, nil, report.Controls{"start": start}},
+	} {
+		tc.controls.AddControlsFromMap(tc.m)
+		if !reflect.DeepEqual(tc.want, tc.controls) {
+			t.Errorf("%s: %s", tc.name, test.Diff(tc.want, tc.controls))
+		}
+	}
+}
+
+func TestControlsMergeBadge(t *testing.T) {
+	older := report.Controls{"approve": {ID: "approve", Human: "Approve", Badge: "3 pending"}}
+	newer := report.Controls{"approve": {ID: "approve", Human: "Approve", Badge: "1 pending"}}
+	if have := older.Merge(newer)["approve"].Badge; have != "1 pending" {
+		t.Errorf("expected the newer badge, got %q", have)
+	}
+	if have := older.Copy()["approve"].Badge; have != "3 pending" {
+		t.Errorf("expected Copy to keep the badge, got %q", have)
+	}
+}
+
+func TestControlRequiresConfirmation(t *testing.T) {
+	for _, testcase := range []struct {
+		control report.Control
+		want    bool
+	}{
+		{report.Control{ID: "foo"}, false},
+		{report.Control{ID: "foo", Confirm: true}, true},
+		{report.Control{ID: "foo", ConfirmText: "Are you sure?"}, true},
+	} {
+		if have := testcase.control.RequiresConfirmation(); testcase.want != have {
+			t.Errorf("%+v.RequiresConfirmation(): want %v, have %v", testcase.control, testcase.want, have)
+		}
+	}
+}
+
+func TestControlsMergeConfirmation(t *testing.T) {
+	unconfirmed := report.Controls{
+		"delete": {ID: "delete", Human: "Delete", Icon: "fa-trash-o"},
+	}
+	confirmed := report.Controls{
+		"delete": {ID: "delete", Human: "Delete", Icon: "fa-trash-o", Confirm: true, ConfirmText: "Delete this container?"},
+	}
+
+	if have := unconfirmed.Merge(confirmed)["delete"]; !have.RequiresConfirmation() || have.ConfirmText != "Delete this container?" {
+		t.Errorf("expected confirmation to be taken from other, got %+v", have)
+	}
+	if have := confirmed.Merge(unconfirmed)["delete"]; have.RequiresConfirmation() {
+		t.Errorf("expected other to take precedence, got %+v", have)
+	}
+	if have := confirmed.Copy()["delete"]; !have.Confirm {
+		t.Errorf("expected copy to preserve confirmation, got %+v", have)
+	}
+}
+
+func TestControlsMergeDisabled(t *testing.T) {
+	controls := report.Controls{
+		"pause": {ID: "pause", Human: "Pause", Icon: "fa-pause", Rank: 1},
+	}
+	other := report.Controls{
+		"unpause": {ID: "unpause", Human: "Unpause", Icon: "fa-play", Rank: 2, Disabled: true, DisabledReason: "container is running"},
+	}
+
+	have, ok := controls.Merge(other)["unpause"]
+	if !ok {
+		t.Fatal("expected disabled control to be present in merged controls")
+	}
+	if have.IsActionable() {
+		t.Errorf("expected disabled control not to be actionable: %+v", have)
+	}
+	if have.DisabledReason != "container is running" {
+		t.Errorf("expected disabled reason to be preserved: %+v", have)
+	}
+	if !controls["pause"].IsActionable() {
+		t.Errorf("expected enabled control to be actionable: %+v", controls["pause"])
+	}
+}
+
+func TestControlsGroupByCategory(t *testing.T) {
+	if have := (report.Controls{}).GroupByCategory(); len(have) != 0 {
+		t.Errorf("expected no groups for empty controls, got %v", have)
+	}
+
+	controls := report.Controls{
+		"stop":    {ID: "stop", Human: "Stop", Rank: 2, Category: "lifecycle"},
+		"start":   {ID: "start", Human: "Start", Rank: 1, Category: "lifecycle"},
+		"restart": {ID: "restart", Human: "Restart", Rank: 2, Category: "lifecycle"},
+		"logs":    {ID: "logs", Human: "Logs", Rank: 0},
+	}
+	want := []report.ControlGroup{
+		{Category: "", Controls: []report.Control{controls["logs"]}},
+		{Category: "lifecycle", Controls: []report.Control{controls["start"], controls["restart"], controls["stop"]}},
+	}
+	for i := 0; i < 10; i++ {
+		if have := controls.GroupByCategory(); !reflect.DeepEqual(want, have) {
+			t.Fatal(test.Diff(want, have))
+		}
+	}
+}
+
+func TestControlsGroupByCategoryRank(t *testing.T) {
+	controls := report.Controls{
+		"stop":   {ID: "stop", Human: "Stop", Rank: 2, Category: "Lifecycle", CategoryRank: 1},
+		"start":  {ID: "start", Human: "Start", Rank: 1, Category: "Lifecycle", CategoryRank: 1},
+		"logs":   {ID: "logs", Human: "Logs", Rank: 1, Category: "Diagnostics", CategoryRank: 2},
+		"exec":   {ID: "exec", Human: "Exec", Rank: 1, Category: "Access", CategoryRank: 2},
+		"delete": {ID: "delete", Human: "Delete", Rank: 1, Category: "Danger", CategoryRank: 9},
+		"attach": {ID: "attach", Human: "Attach", Rank: 1},
+	}
+	want := []report.ControlGroup{
+		{Category: "", Rank: 0, Controls: []report.Control{controls["attach"]}},
+		{Category: "Lifecycle", Rank: 1, Controls: []report.Control{controls["start"], controls["stop"]}},
+		{Category: "Access", Rank: 2, Controls: []report.Control{controls["exec"]}},
+		{Category: "Diagnostics", Rank: 2, Controls: []report.Control{controls["logs"]}},
+		{Category: "Danger", Rank: 9, Controls: []report.Control{controls["delete"]}},
+	}
+	if have := controls.GroupByCategory(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if err := controls.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	controls["restart"] = report.Control{ID: "restart", Human: "Restart", Rank: 3, Category: "Lifecycle", CategoryRank: 5}
+	if err := controls.Validate(); err == nil || !strings.Contains(err.Error(), `category "Lifecycle"`) {
+		t.Errorf("expected a conflicting category rank error, got %v", err)
+	}
+	if have := controls.GroupByCategory()[1]; have.Category != "Lifecycle" || have.Rank != 1 {
+		t.Errorf("expected the lowest rank to be used for a conflicting category, got %+v", have)
+	}
+}
+
+func TestControlsCompact(t *testing.T) {
+	controls := report.Controls{
+		"start":       {ID: "start", Human: "Start", Icon: "fa-play"},
+		"label":       {ID: "label", Human: "Label only"},
+		"icon":        {ID: "icon", Icon: "fa-cog"},
+		"placeholder": {ID: "placeholder"},
+		"ranked":      {ID: "ranked", Rank: 3},
+	}
+	original := controls.Copy()
+	want := report.Controls{
+		"start": {ID: "start", Human: "Start", Icon: "fa-play"},
+		"label": {ID: "label", Human: "Label only"},
+		"icon":  {ID: "icon", Icon: "fa-cog"},
+	}
+	if have := controls.Compact(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if !reflect.DeepEqual(original, controls) {
+		t.Errorf("modified the original: %s", test.Diff(original, controls))
+	}
+	if have := (report.Controls{}).Compact(); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty controls, got %#v", have)
+	}
+}
+
+func TestControlsPrimarySecondary(t *testing.T) {
+	var (
+		start   = report.Control{ID: "start", Human: "Start", Rank: 1}
+		restart = report.Control{ID: "restart", Human: "Restart", Rank: 1}
+		stop    = report.Control{ID: "stop", Human: "Stop", Rank: 2}
+		del     = report.Control{ID: "delete", Human: "Delete", Rank: 9}
+	)
+	for _, tc := range []struct {
+		name          string
+		controls      report.Controls
+		wantPrimary   report.Control
+		wantFound     bool
+		wantSecondary []report.Control
+	}{
+		{"empty", report.Controls{}, report.Control{}, false, []report.Control{}},
+		{"single", report.Controls{"stop": stop}, stop, true, []report.Control{}},
+		{"tie", report.Controls{"start": start, "restart": restart, "stop": stop, "delete": del}, restart, true, []report.Control{start, stop, del}},
+	} {
+		primary, found := tc.controls.Primary()
+		if found != tc.wantFound || !reflect.DeepEqual(tc.wantPrimary, primary) {
+			t.Errorf("%s: Primary: want %v, %v, have %v, %v", tc.name, tc.wantPrimary, tc.wantFound, primary, found)
+		}
+		if have := tc.controls.Secondary(); !reflect.DeepEqual(tc.wantSecondary, have) {
+			t.Errorf("%s: Secondary: %s", tc.name, test.Diff(tc.wantSecondary, have))
+		}
+	}
+}
+
+func TestControlsSorted(t *testing.T) {
+	if have := (report.Controls{}).Sorted(); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty slice, got %#v", have)
+	}
+
+	controls := report.Controls{
+		"b":     {ID: "b", Rank: 1},
+		"a":     {ID: "a", Rank: 1},
+		"first": {ID: "first", Rank: -5},
+		"last":  {ID: "last", Rank: 10},
+	}
+	want := []report.Control{controls["first"], controls["a"], controls["b"], controls["last"]}
+	if have := controls.Sorted(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func TestControlsRemove(t *testing.T) {
+	empty := report.Controls{}
+	empty.RemoveControl("foo")
+	empty.RemoveControls([]string{"foo", "bar"})
+	if len(empty) != 0 {
+		t.Errorf("expected empty controls, got %v", empty)
+	}
+
+	controls := report.Controls{
+		"foo": {ID: "foo"},
+		"bar": {ID: "bar"},
+		"baz": {ID: "baz"},
+		"qux": {ID: "qux"},
+	}
+	controls.RemoveControl("foo")
+	controls.RemoveControls([]string{"bar", "baz", "absent"})
+	want := report.Controls{"qux": {ID: "qux"}}
+	if !reflect.DeepEqual(want, controls) {
+		t.Error(test.Diff(want, controls))
+	}
+}
+
+func TestControlsHasGet(t *testing.T) {
+	foo := report.Control{ID: "foo", Human: "Foo"}
+	controls := report.Controls{"foo": foo}
+
+	if !controls.Has("foo") {
+		t.Error("expected controls to have foo")
+	}
+	if controls.Has("bar") {
+		t.Error("expected controls not to have bar")
+	}
+	if have, ok := controls.Get("foo"); !ok || !reflect.DeepEqual(foo, have) {
+		t.Errorf("Get(foo): want %+v, true; have %+v, %v", foo, have, ok)
+	}
+	if have, ok := controls.Get("bar"); ok || !reflect.DeepEqual(report.Control{}, have) {
+		t.Errorf("Get(bar): want zero control, false; have %+v, %v", have, ok)
+	}
+}
+
+func TestNodeControlsJSON(t *testing.T) {
+	for _, want := range []report.NodeControls{
+		report.MakeNodeControls(),
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("bar", "foo")},
+	} {
+		b, err := json.Marshal(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var have report.NodeControls
+		if err := json.Unmarshal(b, &have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, &codec.JsonHandle{}).Encode(&want); err != nil {
+			t.Fatal(err)
+		}
+		var fromCodec report.NodeControls
+		if err := json.Unmarshal(buf.Bytes(), &fromCodec); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, fromCodec) {
+			t.Error(test.Diff(want, fromCodec))
+		}
+	}
+}
+
+func TestNodeControlDataMergeAssociative(t *testing.T) {
+	t0 := time.Now().UTC()
+	for _, tc := range []struct {
+		name    string
+		a, b, c report.NodeControlData
+		want    report.NodeControlData
+	}{
+		{
+			"dead expires",
+			report.NodeControlData{Dead: true, LastInvoked: t0},
+			report.NodeControlData{LastInvoked: t0.Add(50 * time.Second)},
+			report.NodeControlData{LastInvoked: t0.Add(100 * time.Second)},
+			report.NodeControlData{LastInvoked: t0.Add(100 * time.Second)},
+		},
+		{
+			"dead within the window",
+			report.NodeControlData{Dead: true, LastInvoked: t0},
+			report.NodeControlData{LastInvoked: t0.Add(20 * time.Second)},
+			report.NodeControlData{LastError: "x", LastInvoked: t0.Add(40 * time.Second)},
+			report.NodeControlData{Dead: true, LastError: "x", LastInvoked: t0.Add(40 * time.Second), DeadSince: t0},
+		},
+		{
+			"latest death counts",
+			report.NodeControlData{Dead: true, LastInvoked: t0},
+			report.NodeControlData{Dead: true, LastInvoked: t0.Add(50 * time.Second)},
+			report.NodeControlData{LastInvoked: t0.Add(100 * time.Second)},
+			report.NodeControlData{Dead: true, LastInvoked: t0.Add(100 * time.Second), DeadSince: t0.Add(50 * time.Second)},
+		},
+		{
+			"same time",
+			report.NodeControlData{LastError: "a", LastInvoked: t0.Add(10 * time.Second)},
+			report.NodeControlData{Dead: true, LastInvoked: t0},
+			report.NodeControlData{LastError: "z", LastInvoked: t0.Add(10 * time.Second)},
+			report.NodeControlData{Dead: true, LastError: "z", LastInvoked: t0.Add(10 * time.Second), DeadSince: t0},
+		},
+	} {
+		for _, order := range [][3]report.NodeControlData{
+			{tc.a, tc.b, tc.c},
+			{tc.a, tc.c, tc.b},
+			{tc.b, tc.a, tc.c},
+			{tc.b, tc.c, tc.a},
+			{tc.c, tc.a, tc.b},
+			{tc.c, tc.b, tc.a},
+		} {
+			if have := order[0].Merge(order[1]).Merge(order[2]); !reflect.DeepEqual(tc.want, have) {
+				t.Errorf("%s: (x.Merge(y)).Merge(z): %s", tc.name, test.Diff(tc.want, have))
+			}
+			if have := order[0].Merge(order[1].Merge(order[2])); !reflect.DeepEqual(tc.want, have) {
+				t.Errorf("%s: x.Merge(y.Merge(z)): %s", tc.name, test.Diff(tc.want, have))
+			}
+		}
+	}
+}
+
+func TestNodeControlsMergeUnion(t *testing.T) {
+	t1 := time.Now().UTC()
+	t2 := t1.Add(1 * time.Minute)
+
+	for _, testcase := range []struct {
+		name      string
+		older     report.NodeControls
+		newer     report.NodeControls
+		wantMerge report.NodeControls
+		wantUnion report.NodeControls
+	}{
+		{
+			name:      "overlapping",
+			older:     report.NodeControls{Timestamp: t1, Controls: report.MakeStringSet("a", "b")},
+			newer:     report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("b", "c")},
+			wantMerge: report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("b", "c")},
+			wantUnion: report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("a", "b", "c")},
+		},
+		{
+			name:      "disjoint",
+			older:     report.NodeControls{Timestamp: t1, Controls: report.MakeStringSet("a")},
+			newer:     report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("z")},
+			wantMerge: report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("z")},
+			wantUnion: report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("a", "z")},
+		},
+	} {
+		for _, pair := range [][2]report.NodeControls{
+			{testcase.older, testcase.newer},
+			{testcase.newer, testcase.older},
+		} {
+			if have := pair[0].Merge(pair[1]); !reflect.DeepEqual(testcase.wantMerge, have) {
+				t.Errorf("%s Merge: %s", testcase.name, test.Diff(testcase.wantMerge, have))
+			}
+			if have := pair[0].MergeUnion(pair[1]); !reflect.DeepEqual(testcase.wantUnion, have) {
+				t.Errorf("%s MergeUnion: %s", testcase.name, test.Diff(testcase.wantUnion, have))
+			}
+		}
+	}
+}
+
+func TestNodeControlsMergeEqualTimestamps(t *testing.T) {
+	now := time.Now().UTC()
+	a := report.NodeControls{Timestamp: now, Controls: report.MakeStringSet("a")}
+	b := report.NodeControls{Timestamp: now, Controls: report.MakeStringSet("a", "b")}
+	c := report.NodeControls{Timestamp: now, Controls: report.MakeStringSet("c")}
+
+	want := a.Merge(b).Merge(c)
+	for _, order := range [][3]report.NodeControls{
+		{a, c, b},
+		{b, a, c},
+		{b, c, a},
+		{c, a, b},
+		{c, b, a},
+	} {
+		if have := order[0].Merge(order[1]).Merge(order[2]); !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+		if have := order[0].Merge(order[1].Merge(order[2])); !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestNodeControlsMergeWithStats(t *testing.T) {
+	t1 := time.Now().UTC()
+	t2 := t1.Add(1 * time.Minute)
+	older := report.NodeControls{Timestamp: t1, Controls: report.MakeStringSet("a", "b", "c")}
+	newer := report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("b", "d")}
+	low := report.NodeControls{Timestamp: t1, Controls: report.MakeStringSet("a", "b")}
+	high := report.NodeControls{Timestamp: t1, Controls: report.MakeStringSet("c")}
+
+	for _, testcase := range []struct {
+		name      string
+		nc, other report.NodeControls
+		want      report.NodeControls
+		wantStats report.MergeStats
+	}{
+		{"newer receiver", newer, older, newer, report.MergeStats{ReceiverWon: true, Dropped: 2}},
+		{"newer other", older, newer, newer, report.MergeStats{ReceiverWon: false, Dropped: 2}},
+		{"equal timestamps, receiver wins", high, low, high, report.MergeStats{ReceiverWon: true, SameTimestamps: true, Dropped: 2}},
+		{"equal timestamps, other wins", low, high, high, report.MergeStats{ReceiverWon: false, SameTimestamps: true, Dropped: 2}},
+		{"identical", low, low, low, report.MergeStats{ReceiverWon: true, SameTimestamps: true, Dropped: 0}},
+	} {
+		have, haveStats := testcase.nc.MergeWithStats(testcase.other)
+		if !reflect.DeepEqual(testcase.want, have) {
+			t.Errorf("%s: %s", testcase.name, test.Diff(testcase.want, have))
+		}
+		if want := testcase.nc.Merge(testcase.other); !reflect.DeepEqual(want, have) {
+			t.Errorf("%s: differs from Merge: %s", testcase.name, test.Diff(want, have))
+		}
+		if testcase.wantStats != haveStats {
+			t.Errorf("%s: want %+v, have %+v", testcase.name, testcase.wantStats, haveStats)
+		}
+	}
+}
+
+func TestNodeControlsRemove(t *testing.T) {
+	t1 := time.Now().UTC()
+	t2 := t1.Add(1 * time.Minute)
+	defer mtime.NowReset()
+
+	mtime.NowForce(t1)
+	original := report.MakeNodeControls().Add("a", "b", "c")
+
+	mtime.NowForce(t2)
+	want := report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("b")}
+	if have := original.Remove("a", "c"); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	want = report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("a", "b", "c")}
+	if have := original.Remove("absent"); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	want = report.NodeControls{Timestamp: t1, Controls: report.MakeStringSet("a", "b", "c")}
+	if !reflect.DeepEqual(want, original) {
+		t.Error(test.Diff(want, original))
+	}
+}
+
+func TestNodeControlsToggle(t *testing.T) {
+	t1 := time.Now().UTC()
+	t2 := t1.Add(1 * time.Minute)
+	defer mtime.NowReset()
+
+	mtime.NowForce(t1)
+	original := report.MakeNodeControls().Add("a", "b")
+
+	mtime.NowForce(t2)
+	want := report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("a", "b", "c")}
+	toggled := original.Toggle("c")
+	if !reflect.DeepEqual(want, toggled) {
+		t.Error(test.Diff(want, toggled))
+	}
+	want = report.NodeControls{Timestamp: t2, Controls: report.MakeStringSet("b")}
+	if have := original.Toggle("a"); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	want = report.NodeControls{Timestamp: t2, Controls: original.Controls}
+	if have := toggled.Toggle("c"); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	want = report.NodeControls{Timestamp: t1, Controls: report.MakeStringSet("a", "b")}
+	if !reflect.DeepEqual(want, original) {
+		t.Error(test.Diff(want, original))
+	}
+}
+
+func TestNodeControlsContainsLen(t *testing.T) {
+	for _, testcase := range []struct {
+		nc      report.NodeControls
+		wantLen int
+		wantFoo bool
+	}{
+		{report.NodeControls{}, 0, false},
+		{report.MakeNodeControls(), 0, false},
+		{report.MakeNodeControls().Add("bar"), 1, false},
+		{report.MakeNodeControls().Add("bar", "foo"), 2, true},
+	} {
+		if have := testcase.nc.Len(); testcase.wantLen != have {
+			t.Errorf("%+v.Len(): want %d, have %d", testcase.nc, testcase.wantLen, have)
+		}
+		if have := testcase.nc.Contains("foo"); testcase.wantFoo != have {
+			t.Errorf("%+v.Contains(foo): want %v, have %v", testcase.nc, testcase.wantFoo, have)
+		}
+	}
+}
+
+func TestNodeControlsIsSet(t *testing.T) {
+	unset := report.MakeNodeControls()
+	setEmpty := report.MakeNodeControls().Add("foo").Remove("foo")
+	setPopulated := report.MakeNodeControls().Add("foo")
+
+	if unset.IsSet() || (report.NodeControls{}).IsSet() {
+		t.Error("expected unset NodeControls not to be set")
+	}
+	if !setEmpty.IsSet() || setEmpty.Len() != 0 {
+		t.Errorf("expected empty NodeControls to be set: %+v", setEmpty)
+	}
+	if !setPopulated.IsSet() {
+		t.Errorf("expected populated NodeControls to be set: %+v", setPopulated)
+	}
+
+	for _, have := range []report.NodeControls{
+		unset.Merge(setEmpty),
+		setEmpty.Merge(unset),
+	} {
+		if !reflect.DeepEqual(setEmpty, have) {
+			t.Error(test.Diff(setEmpty, have))
+		}
+	}
+}
+
+func TestControlsEqual(t *testing.T) {
+	a := report.Controls{}
+	a.AddControls([]report.Control{{ID: "foo", Human: "Foo", Rank: 1}, {ID: "bar", Human: "Bar", Rank: 2}})
+	b := report.Controls{}
+	b.AddControls([]report.Control{{ID: "bar", Human: "Bar", Rank: 2}, {ID: "foo", Human: "Foo", Rank: 1}})
+
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Errorf("expected %v to equal %v", a, b)
+	}
+	if !(report.Controls{}).Equal(nil) {
+		t.Error("expected empty controls to equal nil controls")
+	}
+
+	differentField := b.Copy()
+	differentField.AddControl(report.Control{ID: "foo", Human: "Foo", Rank: 3})
+	if a.Equal(differentField) {
+		t.Errorf("expected %v not to equal %v", a, differentField)
+	}
+
+	differentLength := b.Copy()
+	differentLength.AddControl(report.Control{ID: "baz"})
+	if a.Equal(differentLength) || differentLength.Equal(a) {
+		t.Errorf("expected %v not to equal %v", a, differentLength)
+	}
+}
+
+func TestControlValidate(t *testing.T) {
+	for _, testcase := range []struct {
+		control report.Control
+		valid   bool
+	}{
+		{report.Control{ID: "foo", Human: "Foo", Rank: 0}, true},
+		{report.Control{ID: "", Human: "Foo"}, false},
+		{report.Control{ID: "foo", Human: ""}, false},
+		{report.Control{ID: "foo", Human: "Foo", Rank: -1}, false},
+		{report.Control{ID: "foo", Human: "Foo", TimeoutSeconds: 30}, true},
+		{report.Control{ID: "foo", Human: "Foo", TimeoutSeconds: -1}, false},
+		{report.Control{ID: "foo", Human: "Foo", Icon: "fa-trash-o"}, true},
+		{report.Control{ID: "foo", Human: "Foo", Icon: "fa-trashh"}, false},
+		{report.Control{ID: "foo", Human: "Foo", Color: report.DangerControlColor}, true},
+		{report.Control{ID: "foo", Human: "Foo", Color: "red"}, false},
+		{report.Control{ID: "foo", Human: "Foo", Type: report.RPCControlType}, true},
+		{report.Control{ID: "foo", Human: "Foo", Type: report.LinkControlType, URLTemplate: "https://grafana/d/{{hostname}}"}, true},
+		{report.Control{ID: "foo", Human: "Foo", Type: report.LinkControlType}, false},
+		{report.Control{ID: "foo", Human: "Foo", Type: report.RPCControlType, URLTemplate: "https://grafana/d/{{hostname}}"}, false},
+		{report.Control{ID: "foo", Human: "Foo", URLTemplate: "https://grafana/d/{{hostname}}"}, false},
+		{report.Control{ID: "foo", Human: "Foo", Type: "href"}, false},
+	} {
+		if err := testcase.control.Validate(); testcase.valid != (err == nil) {
+			t.Errorf("%+v.Validate(): want valid=%v, have %v", testcase.control, testcase.valid, err)
+		}
+	}
+}
+
+func TestControlsValidate(t *testing.T) {
+	for _, testcase := range []struct {
+		controls report.Controls
+		valid    bool
+	}{
+		{report.Controls{}, true},
+		{report.Controls{"foo": {ID: "foo", Human: "Foo"}, "bar": {ID: "bar", Human: "Bar", Rank: 1}}, true},
+		{report.Controls{"foo": {ID: "bar", Human: "Bar"}}, false},
+		{report.Controls{"foo": {ID: "foo", Human: "Foo"}, "bar": {ID: "bar"}}, false},
+		{report.Controls{"old": {ID: "old", Human: "Old", Deprecated: true, ReplacedBy: "new"}, "new": {ID: "new", Human: "New"}}, true},
+		{report.Controls{"old": {ID: "old", Human: "Old", Deprecated: true, ReplacedBy: "new"}}, false},
+	} {
+		if err := testcase.controls.Validate(); testcase.valid != (err == nil) {
+			t.Errorf("%v.Validate(): want valid=%v, have %v", testcase.controls, testcase.valid, err)
+		}
+	}
+}
+
+func TestControlsValidateKeepsErrorTypes(t *testing.T) {
+	controls := report.Controls{
+		"foo": {ID: "foo", Human: "Foo", Icon: "fa-trashh"},
+		"bar": {ID: "bar", Human: "Bar", Icon: "fa-stop"},
+	}
+	err := controls.Validate()
+	errs, ok := err.(report.ValidationErrors)
+	if !ok || len(errs) != 1 {
+		t.Fatalf("expected one ValidationError, got %#v", err)
+	}
+	if _, ok := errs[0].(report.InvalidIconError); !ok {
+		t.Errorf("expected an InvalidIconError, got %#v", errs[0])
+	}
+	if err := errs.Fatal(); err != nil {
+		t.Errorf("expected an unknown icon not to be fatal, got %v", err)
+	}
+	if want, have := `1 error(s): control "foo" has an unknown icon "fa-trashh"`, err.Error(); want != have {
+		t.Errorf("want %q, have %q", want, have)
+	}
+
+	controls["baz"] = report.Control{ID: "baz"}
+	errs = controls.Validate().(report.ValidationErrors)
+	if fatal, ok := errs.Fatal().(report.ValidationErrors); !ok || len(fatal) != 1 {
+		t.Errorf("expected the empty label to be fatal, got %v", errs.Fatal())
+	}
+}
+
+func TestIsValidIcon(t *testing.T) {
+	for _, icon := range []string{"fa-trash-o", "fa-terminal", "fa-desktop", "fa-play", "fa-pause", "fa-stop"} {
+		if !report.IsValidIcon(icon) {
+			t.Errorf("expected %q to be a valid icon", icon)
+		}
+	}
+	for _, icon := range []string{"", "fa-trashh", "trash-o", "fa fa-trash-o", "fa-lg"} {
+		if report.IsValidIcon(icon) {
+			t.Errorf("expected %q to be an invalid icon", icon)
+		}
+	}
+
+	err := report.Control{ID: "foo", Human: "Foo", Icon: "fa-trashh"}.Validate()
+	if _, ok := err.(report.InvalidIconError); !ok {
+		t.Errorf("expected an InvalidIconError, got %v", err)
+	}
+}
+
+func TestControlsInvalidIcons(t *testing.T) {
+	valid := report.Controls{
+		"start": {ID: "start", Human: "Start", Icon: "fa-play"},
+		"stop":  {ID: "stop", Human: "Stop", Icon: "fa-stop"},
+		"logs":  {ID: "logs", Human: "Logs"},
+	}
+	if have := valid.InvalidIcons(); have == nil || len(have) != 0 {
+		t.Errorf("expected no invalid icons, got %#v", have)
+	}
+
+	invalid := valid.Copy()
+	invalid.AddControls([]report.Control{
+		{ID: "delete", Human: "Delete", Icon: "fa-trashh"},
+		{ID: "exec", Human: "Exec", Icon: "terminal"},
+		{ID: "pause", Human: "Pause", Icon: "fa fa-pause"},
+	})
+	want := map[string]string{
+		"delete": "fa-trashh",
+		"exec":   "terminal",
+		"pause":  "fa fa-pause",
+	}
+	if have := invalid.InvalidIcons(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+// everyFieldChanged returns a copy of the zero Control for each field of
+// Control, with that field set to a non-zero value.
+func everyFieldChanged(t *testing.T) map[string]report.Control {
+	result := map[string]report.Control{}
+	typ := stdreflect.TypeOf(report.Control{})
+	for i := 0; i < typ.NumField(); i++ {
+		changed := report.Control{}
+		field := stdreflect.ValueOf(&changed).Elem().Field(i)
+		switch field.Kind() {
+		case stdreflect.String:
+			field.SetString("x")
+		case stdreflect.Bool:
+			field.SetBool(true)
+		case stdreflect.Int, stdreflect.Int64:
+			field.SetInt(1)
+		case stdreflect.Slice:
+			field.Set(stdreflect.MakeSlice(field.Type(), 1, 1))
+		default:
+			t.Fatalf("unhandled kind %s for field %s", field.Kind(), typ.Field(i).Name)
+		}
+		result[typ.Field(i).Name] = changed
+	}
+	return result
+}
+
+func TestControlsEqualChecksEveryField(t *testing.T) {
+	base := report.Control{}
+	for name, changed := range everyFieldChanged(t) {
+		if (report.Controls{"foo": base}).Equal(report.Controls{"foo": changed}) {
+			t.Errorf("Equal doesn't compare field %s", name)
+		}
+	}
+}
+
+func TestControlEqual(t *testing.T) {
+	scale := func() report.Control {
+		return report.Control{ID: "scale", Human: "Scale", Parameters: []report.ControlParameter{
+			{ID: "replicas", Type: report.IntParameterType},
+			{ID: "strategy", Type: report.EnumParameterType, Options: []string{"recreate", "rolling"}},
+		}}
+	}
+	if !scale().Equal(scale()) {
+		t.Error("expected equal controls to be Equal")
+	}
+
+	base := report.Control{}
+	for name, changed := range everyFieldChanged(t) {
+		if base.Equal(changed) || changed.Equal(base) {
+			t.Errorf("Equal doesn't compare field %s", name)
+		}
+	}
+
+	swapped := scale()
+	swapped.Parameters[0], swapped.Parameters[1] = swapped.Parameters[1], swapped.Parameters[0]
+	if scale().Equal(swapped) {
+		t.Error("Equal should respect the order of Parameters")
+	}
+	swapped = scale()
+	swapped.Parameters[1].Options = []string{"rolling", "recreate"}
+	if scale().Equal(swapped) {
+		t.Error("Equal should respect the order of Options")
+	}
+}
+
+func TestControlsHash(t *testing.T) {
+	a, b := report.Controls{}, report.Controls{}
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("control-%d", i)
+		a[id] = report.Control{ID: id, Human: id, Rank: i}
+	}
+	for i := 19; i >= 0; i-- {
+		id := fmt.Sprintf("control-%d", i)
+		b[id] = report.Control{ID: id, Human: id, Rank: i}
+	}
+	if a.Hash() != b.Hash() {
+		t.Error("equal controls hash differently")
+	}
+	if a.Hash() == (report.Controls{}).Hash() {
+		t.Error("expected different hashes for different controls")
+	}
+
+	base := report.Controls{"foo": {}}.Hash()
+	for name, changed := range everyFieldChanged(t) {
+		if (report.Controls{"foo": changed}).Hash() == base {
+			t.Errorf("Hash doesn't cover field %s", name)
+		}
+	}
+	if (report.Controls{"foo": {}}).Hash() == (report.Controls{"bar": {}}).Hash() {
+		t.Error("Hash doesn't cover keys")
+	}
+	param := report.ControlParameter{Options: []string{"a"}}
+	if (report.Controls{"foo": {Parameters: []report.ControlParameter{{}}}}).Hash() == (report.Controls{"foo": {Parameters: []report.ControlParameter{param}}}).Hash() {
+		t.Error("Hash doesn't cover parameter options")
+	}
+}
+
+func TestControlParameters(t *testing.T) {
+	scale := report.Control{ID: "scale", Human: "Scale", Parameters: []report.ControlParameter{
+		{ID: "replicas", Label: "Replicas", Type: report.IntParameterType, Default: "1"},
+	}}
+	if !scale.HasParameters() {
+		t.Errorf("expected %+v to have parameters", scale)
+	}
+	if (report.Control{ID: "stop"}).HasParameters() {
+		t.Error("expected control without parameters not to have parameters")
+	}
+
+	other := report.Control{ID: "scale", Human: "Scale", Parameters: []report.ControlParameter{
+		{ID: "count", Label: "Count", Type: report.IntParameterType},
+		{ID: "force", Label: "Force", Type: report.BoolParameterType},
+	}}
+	have := report.Controls{"scale": scale}.Merge(report.Controls{"scale": other})
+	if want := (report.Controls{"scale": other}); !want.Equal(have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func TestControlEffectiveColor(t *testing.T) {
+	for _, testcase := range []struct {
+		color, want string
+	}{
+		{"", report.DefaultControlColor},
+		{report.DefaultControlColor, report.DefaultControlColor},
+		{report.WarningControlColor, report.WarningControlColor},
+		{report.DangerControlColor, report.DangerControlColor},
+	} {
+		if have := (report.Control{ID: "foo", Color: testcase.color}).EffectiveColor(); testcase.want != have {
+			t.Errorf("EffectiveColor() of %q: want %q, have %q", testcase.color, testcase.want, have)
+		}
+	}
+}
+
+func TestControlEffectiveAriaLabel(t *testing.T) {
+	for _, testcase := range []struct {
+		control report.Control
+		want    string
+	}{
+		{report.Control{ID: "stop", Human: "Stop"}, "Stop"},
+		{report.Control{ID: "stop", Human: "Stop", AriaLabel: "Stop the container"}, "Stop the container"},
+		{report.Control{ID: "stop"}, ""},
+	} {
+		if have := testcase.control.EffectiveAriaLabel(); testcase.want != have {
+			t.Errorf("EffectiveAriaLabel() of %+v: want %q, have %q", testcase.control, testcase.want, have)
+		}
+	}
+}
+
+func TestControlsFilter(t *testing.T) {
+	controls := report.Controls{
+		"start": {ID: "start", Human: "Start"},
+		"stop":  {ID: "stop", Human: "Stop", Disabled: true},
+	}
+	original := controls.Copy()
+
+	if have := controls.Filter(func(report.Control) bool { return false }); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty controls, got %#v", have)
+	}
+	if have := controls.Filter(func(report.Control) bool { return true }); !reflect.DeepEqual(controls, have) {
+		t.Error(test.Diff(controls, have))
+	}
+	want := report.Controls{"start": controls["start"]}
+	if have := controls.Filter(report.Control.IsActionable); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if !reflect.DeepEqual(original, controls) {
+		t.Error(test.Diff(original, controls))
+	}
+}
+
+func TestControlsFilterByRole(t *testing.T) {
+	controls := report.Controls{
+		"logs":   {ID: "logs", Human: "Logs"},
+		"delete": {ID: "delete", Human: "Delete", RequiredRole: "admin"},
+		"scale":  {ID: "scale", Human: "Scale", RequiredRole: "operator"},
+	}
+	for _, tc := range []struct {
+		roles report.StringSet
+		want  report.Controls
+	}{
+		{nil, report.Controls{"logs": controls["logs"]}},
+		{report.MakeStringSet("viewer"), report.Controls{"logs": controls["logs"]}},
+		{report.MakeStringSet("admin"), report.Controls{"logs": controls["logs"], "delete": controls["delete"]}},
+		{report.MakeStringSet("admin", "operator"), controls},
+	} {
+		if have := controls.FilterByRole(tc.roles); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("roles %v: %s", tc.roles, test.Diff(tc.want, have))
+		}
+	}
+}
+
+func TestControlsActiveControls(t *testing.T) {
+	controls := report.Controls{
+		"rm":     {ID: "rm", Human: "Remove", Deprecated: true, ReplacedBy: "delete"},
+		"delete": {ID: "delete", Human: "Delete"},
+	}
+	want := report.Controls{"delete": controls["delete"]}
+	have := controls.ActiveControls()
+	if !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if _, ok := have[controls["rm"].ReplacedBy]; !ok {
+		t.Errorf("replacement %q of a deprecated control is not active", controls["rm"].ReplacedBy)
+	}
+}
+
+func TestControlsIntersectSubtract(t *testing.T) {
+	var (
+		start = report.Control{ID: "start", Human: "Start"}
+		stop  = report.Control{ID: "stop", Human: "Stop"}
+		exec  = report.Control{ID: "exec", Human: "Exec"}
+		other = report.Control{ID: "stop", Human: "Stop container"}
+	)
+	for _, tc := range []struct {
+		name                    string
+		a, b                    report.Controls
+		intersection, remainder report.Controls
+	}{
+		{
+			"disjoint",
+			report.Controls{"start": start}, report.Controls{"exec": exec},
+			report.Controls{}, report.Controls{"start": start},
+		},
+		{
+			"overlapping",
+			report.Controls{"start": start, "stop": stop}, report.Controls{"stop": other, "exec": exec},
+			report.Controls{"stop": stop}, report.Controls{"start": start},
+		},
+		{
+			"identical",
+			report.Controls{"start": start, "stop": stop}, report.Controls{"start": start, "stop": stop},
+			report.Controls{"start": start, "stop": stop}, report.Controls{},
+		},
+	} {
+		a, b := tc.a.Copy(), tc.b.Copy()
+		if have := tc.a.Intersect(tc.b); !reflect.DeepEqual(tc.intersection, have) {
+			t.Errorf("%s: Intersect: %s", tc.name, test.Diff(tc.intersection, have))
+		}
+		if have := tc.a.Subtract(tc.b); !reflect.DeepEqual(tc.remainder, have) {
+			t.Errorf("%s: Subtract: %s", tc.name, test.Diff(tc.remainder, have))
+		}
+		if !reflect.DeepEqual(a, tc.a) || !reflect.DeepEqual(b, tc.b) {
+			t.Errorf("%s: inputs were modified", tc.name)
+		}
+	}
+}
+
+func TestControlsRename(t *testing.T) {
+	var (
+		rm   = report.Control{ID: "rm", Human: "Remove", Rank: 1}
+		del  = report.Control{ID: "delete", Human: "Delete", Rank: 2}
+		stop = report.Control{ID: "stop", Human: "Stop"}
+	)
+	renamed := rm
+	renamed.ID = "delete"
+
+	for _, tc := range []struct {
+		name     string
+		controls report.Controls
+		want     report.Controls
+	}{
+		{
+			"simple",
+			report.Controls{"rm": rm, "stop": stop},
+			report.Controls{"delete": renamed, "stop": stop},
+		},
+		{
+			"collision",
+			report.Controls{"rm": rm, "delete": del, "stop": stop},
+			report.Controls{"delete": del, "stop": stop},
+		},
+		{
+			"absent",
+			report.Controls{"stop": stop},
+			report.Controls{"stop": stop},
+		},
+	} {
+		original := tc.controls.Copy()
+		if have := tc.controls.Rename("rm", "delete"); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%s: %s", tc.name, test.Diff(tc.want, have))
+		}
+		if !reflect.DeepEqual(original, tc.controls) {
+			t.Errorf("%s: modified the original: %s", tc.name, test.Diff(original, tc.controls))
+		}
+	}
+}
+
+func TestControlsPrefixIDs(t *testing.T) {
+	controls := report.Controls{
+		"restart": {ID: "restart", Human: "Restart"},
+		"rm":      {ID: "rm", Human: "Remove", ParentID: "restart", Deprecated: true, ReplacedBy: "delete"},
+		"delete":  {ID: "delete", Human: "Delete", ParentID: "restart"},
+	}
+	original := controls.Copy()
+	want := report.Controls{
+		"plugin/restart": {ID: "plugin/restart", Human: "Restart"},
+		"plugin/rm":      {ID: "plugin/rm", Human: "Remove", ParentID: "plugin/restart", Deprecated: true, ReplacedBy: "plugin/delete"},
+		"plugin/delete":  {ID: "plugin/delete", Human: "Delete", ParentID: "plugin/restart"},
+	}
+	have := controls.PrefixIDs("plugin/")
+	if !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if err := have.Validate(); err != nil {
+		t.Errorf("references broken by prefixing: %v", err)
+	}
+	if !reflect.DeepEqual(original, controls) {
+		t.Errorf("modified the original: %s", test.Diff(original, controls))
+	}
+
+	nc := report.MakeStringSet("delete", "restart").Map(func(id string) string { return "plugin/" + id })
+	if resolved := have.Filter(func(c report.Control) bool { return nc.Contains(c.ID) }); len(resolved) != 2 {
+		t.Errorf("expected prefixed node control IDs to match prefixed controls, got %v", resolved)
+	}
+}
+
+func TestControlsUpdate(t *testing.T) {
+	controls := report.Controls{
+		"start":  {ID: "start", Human: "Start"},
+		"stop":   {ID: "stop", Human: "Stop", Color: report.WarningControlColor},
+		"delete": {ID: "delete", Human: "Delete", Color: report.DangerControlColor},
+		"kill":   {ID: "kill", Human: "Kill", Color: report.DangerControlColor},
+	}
+	original := controls.Copy()
+	isDanger := func(c report.Control) bool { return c.Color == report.DangerControlColor }
+
+	for _, tc := range []struct {
+		name   string
+		match  func(report.Control) bool
+		mutate func(report.Control) report.Control
+		want   report.Controls
+	}{
+		{
+			"no match",
+			func(report.Control) bool { return false },
+			func(c report.Control) report.Control { c.Disabled = true; return c },
+			original,
+		},
+		{
+			"bulk disable",
+			isDanger,
+			func(c report.Control) report.Control { c.Disabled = true; return c },
+			report.Controls{
+				"start":  {ID: "start", Human: "Start"},
+				"stop":   {ID: "stop", Human: "Stop", Color: report.WarningControlColor},
+				"delete": {ID: "delete", Human: "Delete", Color: report.DangerControlColor, Disabled: true},
+				"kill":   {ID: "kill", Human: "Kill", Color: report.DangerControlColor, Disabled: true},
+			},
+		},
+		{
+			"change ID",
+			func(c report.Control) bool { return c.ID == "start" },
+			func(c report.Control) report.Control { c.ID = "run"; return c },
+			report.Controls{
+				"run":    {ID: "run", Human: "Start"},
+				"stop":   {ID: "stop", Human: "Stop", Color: report.WarningControlColor},
+				"delete": {ID: "delete", Human: "Delete", Color: report.DangerControlColor},
+				"kill":   {ID: "kill", Human: "Kill", Color: report.DangerControlColor},
+			},
+		},
+	} {
+		if have := controls.Update(tc.match, tc.mutate); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%s: %s", tc.name, test.Diff(tc.want, have))
+		}
+		if !reflect.DeepEqual(original, controls) {
+			t.Errorf("%s: modified the original: %s", tc.name, test.Diff(original, controls))
+		}
+	}
+}
+
+func TestControlsSetRank(t *testing.T) {
+	controls := report.Controls{
+		"start": {ID: "start", Human: "Start", Rank: 1},
+		"stop":  {ID: "stop", Human: "Stop", Rank: 2},
+	}
+	original := controls.Copy()
+	for _, tc := range []struct {
+		name string
+		id   string
+		want report.Controls
+	}{
+		{"existing", "stop", report.Controls{
+			"start": {ID: "start", Human: "Start", Rank: 1},
+			"stop":  {ID: "stop", Human: "Stop", Rank: 0},
+		}},
+		{"missing", "restart", original},
+	} {
+		if have := controls.SetRank(tc.id, 0); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%s: %s", tc.name, test.Diff(tc.want, have))
+		}
+		if !reflect.DeepEqual(original, controls) {
+			t.Errorf("%s: modified the original: %s", tc.name, test.Diff(original, controls))
+		}
+	}
+}
+
+func TestControlsLocalize(t *testing.T) {
+	controls := report.Controls{
+		"stop":  {ID: "stop", Human: "Stop", Description: "Stop the container", AriaLabel: "Stop this container"},
+		"pause": {ID: "pause", Human: "Pause"},
+	}
+	original := controls.Copy()
+
+	identity := func(_, human string) string { return human }
+	if have := controls.Localize(identity); !reflect.DeepEqual(controls, have) {
+		t.Error(test.Diff(controls, have))
+	}
+
+	// A catalog keyed by ID, not by the English text.
+	french := map[string]string{
+		"stop":             "Arrêter",
+		"stop.description": "Arrêter le conteneur",
+		"stop.ariaLabel":   "Arrêter ce conteneur",
+	}
+	translate := func(id, human string) string {
+		if translated, ok := french[id]; ok {
+			return translated
+		}
+		return human
+	}
+	want := report.Controls{
+		"stop":  {ID: "stop", Human: "Arrêter", Description: "Arrêter le conteneur", AriaLabel: "Arrêter ce conteneur"},
+		"pause": {ID: "pause", Human: "Pause"},
+	}
+	if have := controls.Localize(translate); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if !reflect.DeepEqual(original, controls) {
+		t.Error(test.Diff(original, controls))
+	}
+}
+
+func TestControlsFindByHuman(t *testing.T) {
+	var (
+		stop     = report.Control{ID: "stop", Human: "Stop", Rank: 3}
+		restart  = report.Control{ID: "restart", Human: "Restart", Rank: 2}
+		logs     = report.Control{ID: "logs", Human: "Show logs", Rank: 1}
+		controls = report.Controls{"stop": stop, "restart": restart, "logs": logs}
+	)
+	for _, tc := range []struct {
+		query string
+		want  []report.Control
+	}{
+		{"", []report.Control{logs, restart, stop}},
+		{"st", []report.Control{restart, stop}},
+		{"ST", []report.Control{restart, stop}},
+		{"LOGS", []report.Control{logs}},
+		{"pause", []report.Control{}},
+	} {
+		if have := controls.FindByHuman(tc.query); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%q: %s", tc.query, test.Diff(tc.want, have))
+		}
+	}
+}
+
+func TestControlsKeys(t *testing.T) {
+	controls := report.Controls{"stop": {ID: "stop"}, "exec": {ID: "exec"}, "start": {ID: "start"}}
+	if want, have := []string{"exec", "start", "stop"}, controls.Keys(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if want, have := report.MakeStringSet("start", "stop", "exec"), controls.KeySet(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+
+	empty := report.Controls{}
+	if have := empty.Keys(); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty keys, got %#v", have)
+	}
+	if have := empty.KeySet(); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty key set, got %#v", have)
+	}
+}
+
+func TestControlsMigrateIcons(t *testing.T) {
+	controls := report.Controls{
+		"delete": {ID: "delete", Icon: "fa-trash-o"},    // renamed in FA5
+		"exec":   {ID: "exec", Icon: "fa-terminal"},     // same in FA5
+		"close":  {ID: "close", Icon: "fa-times"},       // already FA5
+		"weird":  {ID: "weird", Icon: "fa-not-an-icon"}, // unknown
+	}
+	original := controls.Copy()
+	want := report.Controls{
+		"delete": {ID: "delete", Icon: "fa-trash-alt"},
+		"exec":   {ID: "exec", Icon: "fa-terminal"},
+		"close":  {ID: "close", Icon: "fa-times"},
+		"weird":  {ID: "weird", Icon: "fa-not-an-icon"},
+	}
+	if have := controls.MigrateIcons(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if have := controls["delete"].MigrateIcon(); have.Icon != "fa-trash-alt" {
+		t.Errorf("want fa-trash-alt, have %s", have.Icon)
+	}
+	if !reflect.DeepEqual(original, controls) {
+		t.Error(test.Diff(original, controls))
+	}
+}
+
+func TestControlsMigrateIconsStillValid(t *testing.T) {
+	controls := report.Controls{}
+	for _, icon := range []string{"fa-trash-o", "fa-repeat", "fa-refresh", "fa-pencil", "fa-terminal", "fa-warning"} {
+		id := "control" + icon
+		controls[id] = report.Control{ID: id, Human: id, Icon: icon}
+	}
+	if err := controls.Validate(); err != nil {
+		t.Fatalf("FA4 controls should be valid: %v", err)
+	}
+	migrated := controls.MigrateIcons()
+	if err := migrated.Validate(); err != nil {
+		t.Errorf("migrated controls should still be valid: %v", err)
+	}
+	if have := migrated.InvalidIcons(); len(have) != 0 {
+		t.Errorf("expected no invalid icons after migrating, got %v", have)
+	}
+}
+
+func TestControlsVisible(t *testing.T) {
+	controls := report.Controls{
+		"start":    {ID: "start", VisibleWhen: "state==stopped"},
+		"stop":     {ID: "stop", VisibleWhen: "state == running"},
+		"restart":  {ID: "restart", VisibleWhen: "state!=stopped"},
+		"logs":     {ID: "logs"},
+		"garbage":  {ID: "garbage", VisibleWhen: "state running"},
+		"no-key":   {ID: "no-key", VisibleWhen: "==running"},
+		"unpaused": {ID: "unpaused", VisibleWhen: "paused!=true"},
+	}
+	for _, tc := range []struct {
+		state map[string]string
+		want  report.StringSet
+	}{
+		{
+			map[string]string{"state": "running"},
+			report.MakeStringSet("stop", "restart", "logs", "garbage", "no-key", "unpaused"),
+		},
+		{
+			map[string]string{"state": "stopped", "paused": "true"},
+			report.MakeStringSet("start", "logs", "garbage", "no-key"),
+		},
+		{
+			nil,
+			report.MakeStringSet("restart", "logs", "garbage", "no-key", "unpaused"),
+		},
+	} {
+		if have := controls.Visible(tc.state).KeySet(); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%v: %s", tc.state, test.Diff(tc.want, have))
+		}
+	}
+}
+
+func TestControlsForEach(t *testing.T) {
+	controls := report.Controls{
+		"c": {ID: "c", Rank: 3},
+		"a": {ID: "a", Rank: 1},
+		"b": {ID: "b", Rank: 2},
+	}
+	visited := []string{}
+	controls.ForEach(func(c report.Control) bool {
+		visited = append(visited, c.ID)
+		return true
+	})
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(want, visited) {
+		t.Error(test.Diff(want, visited))
+	}
+
+	visited = []string{}
+	controls.ForEach(func(c report.Control) bool {
+		visited = append(visited, c.ID)
+		return c.ID != "b"
+	})
+	if want := []string{"a", "b"}; !reflect.DeepEqual(want, visited) {
+		t.Error(test.Diff(want, visited))
+	}
+}
+
+func TestControlsWithoutCategory(t *testing.T) {
+	controls := report.Controls{
+		"delete": {ID: "delete", Category: "danger"},
+		"kill":   {ID: "kill", Category: "danger"},
+		"logs":   {ID: "logs", Category: "debug"},
+		"exec":   {ID: "exec"},
+	}
+	for _, tc := range []struct {
+		category string
+		want     report.StringSet
+	}{
+		{"danger", report.MakeStringSet("logs", "exec")},
+		{"absent", report.MakeStringSet("delete", "kill", "logs", "exec")},
+		{"", report.MakeStringSet("delete", "kill", "logs")},
+	} {
+		if have := controls.WithoutCategory(tc.category).KeySet(); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%q: %s", tc.category, test.Diff(tc.want, have))
+		}
+	}
+}
+
+func TestControlsClone(t *testing.T) {
+	controls := report.Controls{
+		"scale": {ID: "scale", Parameters: []report.ControlParameter{
+			{ID: "strategy", Type: report.EnumParameterType, Options: []string{"recreate", "rolling"}},
+		}},
+		"stop": {ID: "stop"},
+	}
+	original := report.Controls{
+		"scale": {ID: "scale", Parameters: []report.ControlParameter{
+			{ID: "strategy", Type: report.EnumParameterType, Options: []string{"recreate", "rolling"}},
+		}},
+		"stop": {ID: "stop"},
+	}
+
+	clone := controls.Clone()
+	if !reflect.DeepEqual(original, clone) {
+		t.Error(test.Diff(original, clone))
+	}
+	clone["scale"].Parameters[0].ID = "changed"
+	clone["scale"].Parameters[0].Options[0] = "changed"
+	if !reflect.DeepEqual(original, controls) {
+		t.Errorf("modifying the clone changed the original: %s", test.Diff(original, controls))
+	}
+}
+
+func TestControlsEncodingDeterministic(t *testing.T) {
+	controls := report.Controls{}
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("control-%d", i)
+		controls.AddControl(report.Control{ID: id, Human: id, Rank: i})
+	}
+
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		var want []byte
+		for i := 0; i < 10; i++ {
+			buf := &bytes.Buffer{}
+			if err := codec.NewEncoder(buf, h).Encode(controls.Copy()); err != nil {
+				t.Fatal(err)
+			}
+			if i == 0 {
+				want = buf.Bytes()
+			} else if !bytes.Equal(want, buf.Bytes()) {
+				t.Fatalf("encoding differs:\n%q\n%q", want, buf.Bytes())
+			}
+		}
+
+		have := report.Controls{}
+		if err := codec.NewDecoderBytes(want, h).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(controls, have) {
+			t.Error(test.Diff(controls, have))
+		}
+	}
+}
+
+func TestNodeControlsDecodeTruncated(t *testing.T) {
+	// Only MsgPack, the probe wire format: the vendored JSON decoder never
+	// terminates on an array truncated straight after a comma.
+	h := &codec.MsgpackHandle{}
+	nc := report.NodeControls{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("bar", "foo")}
+	buf := &bytes.Buffer{}
+	if err := codec.NewEncoder(buf, h).Encode(&nc); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	// Every prefix of a valid encoding, including ones that stop part-way
+	// through a map key, must error rather than panic.
+	for i := 0; i < len(encoded); i++ {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("decoding %q panicked: %v", encoded[:i], r)
+				}
+			}()
+			var have report.NodeControls
+			if err := codec.NewDecoderBytes(encoded[:i], h).Decode(&have); err == nil {
+				t.Errorf("decoding %q: expected an error", encoded[:i])
+			}
+		}()
+	}
+}
+
+func TestNodeControlsDecodeUnknownKeys(t *testing.T) {
+	payload := map[string]interface{}{
+		"timestamp": "2017-03-14T15:09:26Z",
+		"controls":  []string{"foo"},
+		"extra":     "from a newer probe",
+	}
+	want := report.MakeNodeControls().WithTimestamp(time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC), "foo")
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := []byte{}
+		if err := codec.NewEncoderBytes(&buf, h).Encode(payload); err != nil {
+			t.Fatal(err)
+		}
+
+		have := report.NodeControls{}
+		if err := codec.NewDecoderBytes(buf, h).Decode(&have); err != nil {
+			t.Fatalf("lenient: %v", err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("lenient: %s", test.Diff(want, have))
+		}
+
+		if err := codec.NewDecoderBytes(buf, report.StrictHandle(h)).Decode(&have); err == nil {
+			t.Error("strict: expected an error for the unknown key")
+		}
+		if err := codec.NewDecoderBytes(buf, h).Decode(&have); err != nil {
+			t.Errorf("StrictHandle modified the original handle: %v", err)
+		}
+
+		buf = buf[:0]
+		if err := codec.NewEncoderBytes(&buf, h).Encode(want); err != nil {
+			t.Fatal(err)
+		}
+		have = report.NodeControls{}
+		if err := codec.NewDecoderBytes(buf, report.StrictHandle(h)).Decode(&have); err != nil {
+			t.Fatalf("strict: %v", err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("strict: %s", test.Diff(want, have))
+		}
+	}
+}
+
+func TestNodeControlsDiff(t *testing.T) {
+	now := time.Now()
+	at := func(ids ...string) report.NodeControls {
+		return report.MakeNodeControls().WithTimestamp(now, ids...)
+	}
+	for _, tc := range []struct {
+		name           string
+		current, prev  report.NodeControls
+		added, removed report.StringSet
+	}{
+		{"additions", at("a", "b", "c"), at("a"), report.MakeStringSet("b", "c"), nil},
+		{"removals", at("a"), at("a", "b", "c"), nil, report.MakeStringSet("b", "c")},
+		{"both", at("a", "c"), at("a", "b"), report.MakeStringSet("c"), report.MakeStringSet("b")},
+		{"no change", at("a", "b"), at("a", "b"), nil, nil},
+		{"unset previous", at("a", "b"), report.MakeNodeControls(), report.MakeStringSet("a", "b"), nil},
+	} {
+		added, removed := tc.current.Diff(tc.prev)
+		if !reflect.DeepEqual(tc.added, added) {
+			t.Errorf("%s: added: %s", tc.name, test.Diff(tc.added, added))
+		}
+		if !reflect.DeepEqual(tc.removed, removed) {
+			t.Errorf("%s: removed: %s", tc.name, test.Diff(tc.removed, removed))
+		}
+	}
+}
+
+func TestNodeControlsEncodingConcurrent(t *testing.T) {
+	var (
+		wg     sync.WaitGroup
+		errors = make(chan string, 100)
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			want := report.MakeNodeControls().WithTimestamp(time.Unix(int64(i), 0).UTC(), fmt.Sprintf("control-%d", i))
+			for j := 0; j < 100; j++ {
+				buf := []byte{}
+				if err := codec.NewEncoderBytes(&buf, &codec.MsgpackHandle{}).Encode(&want); err != nil {
+					errors <- err.Error()
+					return
+				}
+				var have report.NodeControls
+				if err := codec.NewDecoderBytes(buf, &codec.MsgpackHandle{}).Decode(&have); err != nil {
+					errors <- err.Error()
+					return
+				}
+				if !reflect.DeepEqual(want, have) {
+					errors <- test.Diff(want, have)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errors)
+	for err := range errors {
+		t.Error(err)
+	}
+}
+
+func TestNodeControlsValidateAgainst(t *testing.T) {
+	var (
+		nc        = report.MakeNodeControls().Add("start", "stop", "exec")
+		available = report.Controls{"start": {ID: "start"}, "stop": {ID: "stop"}, "exec": {ID: "exec"}, "pause": {ID: "pause"}}
+	)
+	for _, tc := range []struct {
+		name      string
+		available report.Controls
+		want      report.StringSet
+	}{
+		{"all resolve", available, nil},
+		{"some unknown", report.Controls{"start": {ID: "start"}}, report.MakeStringSet("exec", "stop")},
+		{"none available", report.Controls{}, report.MakeStringSet("exec", "start", "stop")},
+	} {
+		if have := nc.ValidateAgainst(tc.available); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%s: %s", tc.name, test.Diff(tc.want, have))
+		}
+	}
+}
+
+func TestNodeControlsEncoding(t *testing.T) {
+	for _, want := range []report.NodeControls{
+		report.MakeNodeControls(),
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("foo")},
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("bar", "baz", "foo")},
+	} {
+		for _, h := range []codec.Handle{
+			codec.Handle(&codec.MsgpackHandle{}),
+			codec.Handle(&codec.JsonHandle{}),
+		} {
+			buf := &bytes.Buffer{}
+			if err := codec.NewEncoder(buf, h).Encode(&want); err != nil {
+				t.Fatal(err)
+			}
+			var have report.NodeControls
+			if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(want, have) {
+				t.Error(test.Diff(want, have))
+			}
+		}
+	}
+}
+
+func TestEncodeDecodeHooks(t *testing.T) {
+	defer func() { report.EncodeHook, report.DecodeHook = nil, nil }()
+	var encodedControls, encodedNodeControls, decodedControls, decodedNodeControls int
+	report.EncodeHook = func(controls, nodeControls int) {
+		encodedControls += controls
+		encodedNodeControls += nodeControls
+	}
+	report.DecodeHook = func(controls, nodeControls int) {
+		decodedControls += controls
+		decodedNodeControls += nodeControls
+	}
+
+	in := struct {
+		Controls     report.Controls       `json:"controls"`
+		NodeControls []report.NodeControls `json:"nodeControls"`
+	}{
+		Controls: report.Controls{
+			"start": {ID: "start", Human: "Start"},
+			"stop":  {ID: "stop", Human: "Stop"},
+			"pause": {ID: "pause", Human: "Pause"},
+		},
+		NodeControls: []report.NodeControls{
+			report.MakeNodeControls().Add("start"),
+			report.MakeNodeControls().Add("stop", "pause"),
+		},
+	}
+	buf := []byte{}
+	if err := codec.NewEncoderBytes(&buf, &codec.MsgpackHandle{}).Encode(&in); err != nil {
+		t.Fatal(err)
+	}
+	if encodedControls != 3 || encodedNodeControls != 2 {
+		t.Errorf("encode: want 3 controls and 2 NodeControls, have %d and %d", encodedControls, encodedNodeControls)
+	}
+	out := in
+	out.Controls, out.NodeControls = nil, nil
+	if err := codec.NewDecoderBytes(buf, &codec.MsgpackHandle{}).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if decodedControls != 3 || decodedNodeControls != 2 {
+		t.Errorf("decode: want 3 controls and 2 NodeControls, have %d and %d", decodedControls, decodedNodeControls)
+	}
+}
+
+func TestNodeControlsGob(t *testing.T) {
+	for _, want := range []report.NodeControls{
+		report.MakeNodeControls(),
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("foo")},
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("bar", "baz", "foo")},
+	} {
+		buf := &bytes.Buffer{}
+		if err := gob.NewEncoder(buf).Encode(want); err != nil {
+			t.Fatal(err)
+		}
+		var have report.NodeControls
+		if err := gob.NewDecoder(buf).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !want.Timestamp.Equal(have.Timestamp) || !want.Controls.Equal(have.Controls) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+
+	// As part of a larger structure, such as a cached report.
+	in := map[string]report.NodeControls{
+		"node1": report.MakeNodeControls().WithTimestamp(time.Now().UTC(), "start", "stop"),
+	}
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	out := map[string]report.NodeControls{}
+	if err := gob.NewDecoder(buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Error(test.Diff(in, out))
+	}
+}
+
+func TestNodeControlsEncodingBatch(t *testing.T) {
+	want := makeBenchmarkNodeControlsBatch(100)
+	for id, nc := range want {
+		want[id] = report.MakeNodeControls().WithTimestamp(nc.Timestamp.UTC(), nc.Controls...)
+	}
+	want["empty"] = report.MakeNodeControls()
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := []byte{}
+		if err := codec.NewEncoderBytes(&buf, h).Encode(want); err != nil {
+			t.Fatal(err)
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				have := map[string]report.NodeControls{}
+				if err := codec.NewDecoderBytes(buf, h).Decode(&have); err != nil {
+					t.Error(err)
+					return
+				}
+				if !reflect.DeepEqual(want, have) {
+					t.Error(test.Diff(want, have))
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestNodeControlsCBOR(t *testing.T) {
+	for _, want := range []report.NodeControls{
+		report.MakeNodeControls(),
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("foo")},
+		{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("bar", "baz", "foo")},
+	} {
+		b, err := want.EncodeCBOR()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var have report.NodeControls
+		if err := have.DecodeCBOR(b); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestNodeControlsCBORMatchesMsgpack(t *testing.T) {
+	now := time.Now().UTC()
+	in := struct {
+		Timestamp string   `codec:"timestamp"`
+		Controls  []string `codec:"controls"`
+	}{now.Format(time.RFC3339Nano), []string{"bar", "foo"}}
+
+	var fromMsgpack, fromCBOR report.NodeControls
+	for _, testcase := range []struct {
+		h   codec.Handle
+		out *report.NodeControls
+	}{
+		{&codec.MsgpackHandle{}, &fromMsgpack},
+		{&codec.CborHandle{}, &fromCBOR},
+	} {
+		buf := []byte{}
+		if err := codec.NewEncoderBytes(&buf, testcase.h).Encode(in); err != nil {
+			t.Fatal(err)
+		}
+		if err := codec.NewDecoderBytes(buf, testcase.h).Decode(testcase.out); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !reflect.DeepEqual(fromMsgpack, fromCBOR) {
+		t.Error(test.Diff(fromMsgpack, fromCBOR))
+	}
+	if want := (report.NodeControls{Timestamp: now, Controls: report.MakeStringSet("bar", "foo")}); !reflect.DeepEqual(want, fromCBOR) {
+		t.Error(test.Diff(want, fromCBOR))
+	}
+}
+
+func TestNodeControlsDecodeBinaryTimestamp(t *testing.T) {
+	want := report.NodeControls{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("bar", "foo")}
+	binaryTimestamp, err := want.Timestamp.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := struct {
+		Timestamp []byte   `codec:"timestamp"`
+		Controls  []string `codec:"controls"`
+	}{binaryTimestamp, want.Controls}
+
+	for _, h := range []*codec.MsgpackHandle{
+		{},
+		{WriteExt: true}, // encodes []byte as msgpack bin, rather than raw
+	} {
+		for _, in := range []interface{}{&want, &legacy} {
+			buf := &bytes.Buffer{}
+			if err := codec.NewEncoder(buf, h).Encode(in); err != nil {
+				t.Fatal(err)
+			}
+			var have report.NodeControls
+			if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(want, have) {
+				t.Error(test.Diff(want, have))
+			}
+		}
+	}
+}
+
+func TestNodeControlsUnmarshalJSONMatchesCodec(t *testing.T) {
+	want := report.NodeControls{Timestamp: time.Now().UTC(), Controls: report.MakeStringSet("bar", "foo")}
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromJSON, fromCodec report.NodeControls
+	if err := json.Unmarshal(b, &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+	if err := codec.NewDecoderBytes(b, &codec.JsonHandle{}).Decode(&fromCodec); err != nil {
+		t.Fatal(err)
+	}
+	for _, have := range []report.NodeControls{fromJSON, fromCodec} {
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestNodeControlDataEncoding(t *testing.T) {
+	now := time.Now().UTC()
+	want := report.MakeNodeControlDataLatestMap().
+		Set("foo", now, report.NodeControlData{}).
+		Set("bar", now, report.NodeControlData{Dead: true, LastError: "container not found", LastInvoked: now}).
+		Set("baz", now, report.NodeControlData{Dead: true, LastInvoked: now, DeadSince: now.Add(-10 * time.Second)})
+
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(&want); err != nil {
+			t.Fatal(err)
+		}
+		have := report.MakeNodeControlDataLatestMap()
+		if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestNodeControlDataEncodingOmitsZeroTimes(t *testing.T) {
+	var zero report.NodeControlData
+	buf := &bytes.Buffer{}
+	if err := codec.NewEncoder(buf, &codec.JsonHandle{}).Encode(&zero); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := `{"dead":false}`, buf.String(); want != have {
+		t.Errorf("codec: want %s, have %s", want, have)
+	}
+	b, err := json.Marshal(zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, have := `{"dead":false}`, string(b); want != have {
+		t.Errorf("encoding/json: want %s, have %s", want, have)
+	}
+
+	now := time.Now().UTC()
+	want := report.NodeControlData{Dead: true, LastInvoked: now, DeadSince: now.Add(-time.Second)}
+	var have report.NodeControlData
+	b, err = json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &have); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func TestControlResultEncoding(t *testing.T) {
+	now := time.Now().UTC()
+	want := report.MakeControlResultLatestMap().
+		Set("restart", now, report.ControlResult{ControlID: "restart", Success: true, CompletedAt: now}).
+		Set("stop", now, report.ControlResult{ControlID: "stop", Message: "container not found", CompletedAt: now})
+
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(&want); err != nil {
+			t.Fatal(err)
+		}
+		have := report.MakeControlResultLatestMap()
+		if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestControlResultMerge(t *testing.T) {
+	t1 := time.Now().UTC()
+	t2 := t1.Add(1 * time.Minute)
+	older := report.ControlResult{ControlID: "stop", Message: "timed out", CompletedAt: t1}
+	newer := report.ControlResult{ControlID: "stop", Success: true, CompletedAt: t2}
+
+	for _, have := range []report.ControlResult{older.Merge(newer), newer.Merge(older)} {
+		if !reflect.DeepEqual(newer, have) {
+			t.Error(test.Diff(newer, have))
+		}
+	}
+
+	var (
+		a    = report.MakeControlResultLatestMap().Set("stop", t1, older)
+		b    = report.MakeControlResultLatestMap().Set("stop", t2, newer)
+		want = report.MakeControlResultLatestMap().Set("stop", t2, newer)
+	)
+	for _, have := range []report.ControlResultLatestMap{a.Merge(b), b.Merge(a)} {
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestControlProgressEncoding(t *testing.T) {
+	now := time.Now().UTC()
+	want := report.MakeControlProgressLatestMap().
+		Set("drain", now, report.ControlProgress{ControlID: "drain", Percent: 40, Message: "evicting pods"}).
+		Set("pull", now, report.ControlProgress{ControlID: "pull", Percent: 100})
+
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(&want); err != nil {
+			t.Fatal(err)
+		}
+		have := report.MakeControlProgressLatestMap()
+		if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestControlProgressMerge(t *testing.T) {
+	var (
+		t1   = time.Now().UTC()
+		t2   = t1.Add(1 * time.Second)
+		a    = report.MakeControlProgressLatestMap().Set("drain", t1, report.ControlProgress{ControlID: "drain", Percent: 10})
+		b    = report.MakeControlProgressLatestMap().Set("drain", t2, report.ControlProgress{ControlID: "drain", Percent: 20})
+		want = report.MakeControlProgressLatestMap().Set("drain", t2, report.ControlProgress{ControlID: "drain", Percent: 20})
+	)
+	for _, have := range []report.ControlProgressLatestMap{a.Merge(b), b.Merge(a)} {
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestControlAuditEncoding(t *testing.T) {
+	now := time.Now().UTC()
+	want := report.MakeControlAuditLatestMap().
+		Set("restart", now, report.ControlAudit{ControlID: "restart", User: "alice", Source: "ui", InvokedAt: now}).
+		Set("stop", now, report.ControlAudit{ControlID: "stop", User: "bob", InvokedAt: now})
+
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(&want); err != nil {
+			t.Fatal(err)
+		}
+		have := report.MakeControlAuditLatestMap()
+		if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestControlAuditMerge(t *testing.T) {
+	t1 := time.Now().UTC()
+	t2 := t1.Add(3 * time.Minute)
+	older := report.ControlAudit{ControlID: "stop", User: "bob", InvokedAt: t1}
+	newer := report.ControlAudit{ControlID: "stop", User: "alice", Source: "api", InvokedAt: t2}
+
+	for _, have := range []report.ControlAudit{older.Merge(newer), newer.Merge(older)} {
+		if !reflect.DeepEqual(newer, have) {
+			t.Error(test.Diff(newer, have))
+		}
+	}
+
+	var (
+		a    = report.MakeControlAuditLatestMap().Set("stop", t1, older)
+		b    = report.MakeControlAuditLatestMap().Set("stop", t2, newer)
+		want = report.MakeControlAuditLatestMap().Set("stop", t2, newer)
+	)
+	for _, have := range []report.ControlAuditLatestMap{a.Merge(b), b.Merge(a)} {
+		if !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestNodeControlDataMerge(t *testing.T) {
+	t1 := time.Now().UTC()
+	t2 := t1.Add(1 * time.Minute)
+	older := report.NodeControlData{LastError: "timed out", LastInvoked: t1}
+	newer := report.NodeControlData{LastInvoked: t2}
+
+	for _, have := range []report.NodeControlData{older.Merge(newer), newer.Merge(older)} {
+		if !reflect.DeepEqual(newer, have) {
+			t.Error(test.Diff(newer, have))
+		}
+	}
+}
+
+func TestNodeControlDataMergeDead(t *testing.T) {
+	var (
+		t1     = time.Now().UTC()
+		within = t1.Add(30 * time.Second)
+		after  = t1.Add(1 * time.Hour)
+	)
+	for _, tc := range []struct {
+		name string
+		a, b report.NodeControlData
+		want report.NodeControlData
+	}{
+		{
+			"neither dead",
+			report.NodeControlData{LastInvoked: t1},
+			report.NodeControlData{LastInvoked: within},
+			report.NodeControlData{LastInvoked: within},
+		},
+		{
+			"both dead",
+			report.NodeControlData{Dead: true, LastInvoked: t1},
+			report.NodeControlData{Dead: true, LastError: "gone", LastInvoked: within},
+			report.NodeControlData{Dead: true, LastError: "gone", LastInvoked: within},
+		},
+		{
+			"newer dead",
+			report.NodeControlData{LastInvoked: t1},
+			report.NodeControlData{Dead: true, LastInvoked: within},
+			report.NodeControlData{Dead: true, LastInvoked: within},
+		},
+		{
+			"older dead, within the window",
+			report.NodeControlData{Dead: true, LastError: "gone", LastInvoked: t1},
+			report.NodeControlData{LastInvoked: within},
+			report.NodeControlData{Dead: true, LastInvoked: within, DeadSince: t1},
+		},
+		{
+			"older dead, outside the window",
+			report.NodeControlData{Dead: true, LastInvoked: t1},
+			report.NodeControlData{LastInvoked: after},
+			report.NodeControlData{LastInvoked: after},
+		},
+		{
+			"same time",
+			report.NodeControlData{LastError: "a", LastInvoked: t1},
+			report.NodeControlData{LastError: "b", LastInvoked: t1},
+			report.NodeControlData{LastError: "b", LastInvoked: t1},
+		},
+		{
+			"same time, one dead",
+			report.NodeControlData{LastError: "z", LastInvoked: t1},
+			report.NodeControlData{Dead: true, LastInvoked: t1},
+			report.NodeControlData{Dead: true, LastInvoked: t1},
+		},
+	} {
+		for _, have := range []report.NodeControlData{tc.a.Merge(tc.b), tc.b.Merge(tc.a)} {
+			if !reflect.DeepEqual(tc.want, have) {
+				t.Errorf("%s: %s", tc.name, test.Diff(tc.want, have))
+			}
+		}
+	}
+}
+
+func TestNodeControlsWithTimestamp(t *testing.T) {
+	timestamp := time.Date(2017, time.October, 1, 12, 30, 0, 123456789, time.UTC)
+	want := report.NodeControls{Timestamp: timestamp, Controls: report.MakeStringSet("bar", "foo")}
+	nc := report.MakeNodeControls().WithTimestamp(timestamp, "foo", "bar")
+	if !reflect.DeepEqual(want, nc) {
+		t.Error(test.Diff(want, nc))
+	}
+
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(&nc); err != nil {
+			t.Fatal(err)
+		}
+		var have report.NodeControls
+		if err := codec.NewDecoder(buf, h).Decode(&have); err != nil {
+			t.Fatal(err)
+		}
+		if !have.Timestamp.Equal(timestamp) {
+			t.Errorf("want timestamp %s, have %s", timestamp, have.Timestamp)
+		}
+	}
+}
+
+func TestControlsDiff(t *testing.T) {
+	controls := report.Controls{
+		"same":    {ID: "same", Human: "Same"},
+		"changed": {ID: "changed", Human: "Before"},
+		"removed": {ID: "removed", Human: "Removed"},
+	}
+	other := report.Controls{
+		"same":    {ID: "same", Human: "Same"},
+		"changed": {ID: "changed", Human: "After"},
+		"added":   {ID: "added", Human: "Added"},
+	}
+
+	added, removed, changed := controls.Diff(other)
+	for _, testcase := range []struct {
+		name       string
+		want, have report.Controls
+	}{
+		{"added", report.Controls{"added": other["added"]}, added},
+		{"removed", report.Controls{"removed": controls["removed"]}, removed},
+		{"changed", report.Controls{"changed": other["changed"]}, changed},
+	} {
+		if !reflect.DeepEqual(testcase.want, testcase.have) {
+			t.Errorf("%s: %s", testcase.name, test.Diff(testcase.want, testcase.have))
+		}
+	}
+
+	added, removed, changed = controls.Diff(controls.Copy())
+	for _, have := range []report.Controls{added, removed, changed} {
+		if have == nil || len(have) != 0 {
+			t.Errorf("expected non-nil empty controls, got %#v", have)
+		}
+	}
+}
+
+func TestControlCooldownEncoding(t *testing.T) {
+	control := report.Control{ID: "restart", Human: "Restart", Cooldown: 2 * time.Second}
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(&control); err != nil {
+			t.Fatal(err)
+		}
+		var wire struct {
+			Cooldown int64 `json:"cooldown"`
+		}
+		if err := codec.NewDecoder(buf, h).Decode(&wire); err != nil {
+			t.Fatal(err)
+		}
+		if wire.Cooldown != 2000 {
+			t.Errorf("expected cooldown to be encoded as 2000 milliseconds, got %d", wire.Cooldown)
+		}
+	}
+
+	// encoding/json must agree with the codec on the unit.
+	b, err := json.Marshal(control)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte(`"cooldown":2000`)) || bytes.Contains(b, []byte(`"cooldown":2000000000`)) {
+		t.Errorf("expected cooldown to be marshalled as 2000 milliseconds, got %s", b)
+	}
+	var have report.Control
+	if err := json.Unmarshal(b, &have); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(control, have) {
+		t.Error(test.Diff(control, have))
+	}
+}
+
+var benchmarkNodeControls = []struct {
+	name string
+	size int
+}{
+	{"small", 2},
+	{"medium", 20},
+	{"large", 500},
+}
+
+func makeBenchmarkNodeControls(size int) report.NodeControls {
+	ids := make([]string, 0, size)
+	for i := 0; i < size; i++ {
+		ids = append(ids, fmt.Sprintf("control-%d", i))
+	}
+	return report.MakeNodeControls().Add(ids...)
+}
+
+func BenchmarkNodeControlsEncode(b *testing.B) {
+	// Baseline (MsgPack, linux/amd64):
+	//   small    1.9µs/op   3.1kB/op   10 allocs/op
+	//   medium   2.9µs/op   3.9kB/op   12 allocs/op
+	//   large   20.5µs/op  19.3kB/op   16 allocs/op
+	for _, bm := range benchmarkNodeControls {
+		nc := makeBenchmarkNodeControls(bm.size)
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				buf := &bytes.Buffer{}
+				codec.NewEncoder(buf, &codec.MsgpackHandle{}).Encode(&nc)
+			}
+		})
+	}
+}
+
+func BenchmarkNodeControlsDecode(b *testing.B) {
+	// Baseline (MsgPack, linux/amd64):
+	//   small    1.3µs/op   1.4kB/op    9 allocs/op
+	//   medium   2.8µs/op   2.0kB/op   27 allocs/op
+	//   large   47.1µs/op  17.5kB/op  507 allocs/op
+	for _, bm := range benchmarkNodeControls {
+		nc := makeBenchmarkNodeControls(bm.size)
+		buf := &bytes.Buffer{}
+		codec.NewEncoder(buf, &codec.MsgpackHandle{}).Encode(&nc)
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var have report.NodeControls
+				codec.NewDecoderBytes(buf.Bytes(), &codec.MsgpackHandle{}).Decode(&have)
+			}
+		})
+	}
+}
+
+// makeBenchmarkNodeControlsBatch makes NodeControls for n nodes, which
+// mostly share the same few controls, as the containers in a report do.
+func makeBenchmarkNodeControlsBatch(n int) map[string]report.NodeControls {
+	ids := []string{"docker_attach_container", "docker_exec_container", "docker_pause_container", "docker_restart_container", "docker_stop_container"}
+	batch := map[string]report.NodeControls{}
+	for i := 0; i < n; i++ {
+		batch[fmt.Sprintf("container-%d", i)] = report.MakeNodeControls().Add(ids[:1+i%len(ids)]...)
+	}
+	return batch
+}
+
+func BenchmarkNodeControlsDecodeBatch(b *testing.B) {
+	// MsgPack, linux/amd64:
+	//   without interning   1.0ms/op  567kB/op  8028 allocs/op
+	//   with interning      1.0ms/op  495kB/op  5028 allocs/op
+	batch := makeBenchmarkNodeControlsBatch(1000)
+	buf := []byte{}
+	codec.NewEncoderBytes(&buf, &codec.MsgpackHandle{}).Encode(batch)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		have := map[string]report.NodeControls{}
+		codec.NewDecoderBytes(buf, &codec.MsgpackHandle{}).Decode(&have)
+	}
+}
+
+func makeBenchmarkControls(start, finish int) report.Controls {
+	controls := report.Controls{}
+	for i := start; i < finish; i++ {
+		id := fmt.Sprintf("control-%d", i)
+		controls.AddControl(report.Control{ID: id, Human: id, Rank: i})
+	}
+	return controls
+}
+
+func TestControlsMergeMany(t *testing.T) {
+	a, b, c := makeBenchmarkControls(0, 10), makeBenchmarkControls(5, 15), makeBenchmarkControls(12, 20)
+	c.AddControl(report.Control{ID: "control-0", Human: "Overridden"})
+
+	want := a.Merge(b).Merge(c)
+	if have := a.MergeMany(b, c); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if have := a.MergeMany(); !reflect.DeepEqual(a, have) {
+		t.Error(test.Diff(a, have))
+	}
+	if have := (report.Controls{}).MergeMany(); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty controls, got %#v", have)
+	}
+}
+
+var benchmarkControlsResult report.Controls
+
+func BenchmarkControlsMergeLoop(b *testing.B) {
+	sources := []report.Controls{}
+	for i := 0; i < 10; i++ {
+		sources = append(sources, makeBenchmarkControls(i*10, i*10+20))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		result := report.Controls{}
+		for _, source := range sources {
+			result = result.Merge(source)
+		}
+		benchmarkControlsResult = result
+	}
+}
+
+func BenchmarkControlsMergeMany(b *testing.B) {
+	sources := []report.Controls{}
+	for i := 0; i < 10; i++ {
+		sources = append(sources, makeBenchmarkControls(i*10, i*10+20))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		benchmarkControlsResult = report.Controls{}.MergeMany(sources...)
+	}
+}
+
+func TestControlsMergeEmpty(t *testing.T) {
+	controls := report.Controls{"foo": {ID: "foo", Human: "Foo"}}
+	want := controls.Copy()
+
+	if have := controls.Merge(report.Controls{}); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	if have := controls.Merge(nil); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+
+	// Merging into an empty Controls must not alias other.
+	have := (report.Controls{}).Merge(controls)
+	if !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	have.AddControl(report.Control{ID: "bar", Human: "Bar"})
+	if !reflect.DeepEqual(want, controls) {
+		t.Error(test.Diff(want, controls))
+	}
+}
+
+func TestControlsMergeBothEmpty(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		cs, other report.Controls
+	}{
+		{"nil with nil", nil, nil},
+		{"nil with empty", nil, report.Controls{}},
+		{"empty with nil", report.Controls{}, nil},
+	} {
+		have := tc.cs.Merge(tc.other)
+		if have == nil {
+			t.Errorf("%s: expected a non-nil Controls", tc.name)
+			continue
+		}
+		have.AddControl(report.Control{ID: "foo", Human: "Foo"})
+		if len(tc.cs) != 0 || len(tc.other) != 0 {
+			t.Errorf("%s: adding to the result modified the inputs", tc.name)
+		}
+	}
+
+	// A Topology decoded without controls must still take them once merged.
+	topology := report.Topology{}.Merge(report.Topology{})
+	topology.Controls.AddControl(report.Control{ID: "foo", Human: "Foo"})
+}
+
+func BenchmarkControlsMergeEmpty(b *testing.B) {
+	controls := makeBenchmarkControls(0, 20)
+	empty := report.Controls{}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		benchmarkControlsResult = controls.Merge(empty)
+	}
+}
+
+func TestControlsString(t *testing.T) {
+	controls := report.Controls{
+		"stop":  {ID: "stop", Human: "Stop", Rank: 2},
+		"start": {ID: "start", Human: "Start", Rank: 1},
+	}
+	if want, have := "start (Start) rank=1", controls["start"].String(); want != have {
+		t.Errorf("want %q, have %q", want, have)
+	}
+	if want, have := "start (Start) rank=1\nstop (Stop) rank=2", controls.String(); want != have {
+		t.Errorf("want %q, have %q", want, have)
+	}
+	if want, have := "", (report.Controls{}).String(); want != have {
+		t.Errorf("want %q, have %q", want, have)
+	}
+}
+
+func TestNodeControlDataLatestMapPruneDead(t *testing.T) {
+	var (
+		now    = time.Now()
+		cutoff = now.Add(-time.Minute)
+		have   = report.MakeNodeControlDataLatestMap().
+			Set("live", now.Add(-time.Hour), report.NodeControlData{}).
+			Set("recently-dead", now, report.NodeControlData{Dead: true}).
+			Set("long-dead", now.Add(-time.Hour), report.NodeControlData{Dead: true}).
+			PruneDead(cutoff)
+		want = report.MakeNodeControlDataLatestMap().
+			Set("live", now.Add(-time.Hour), report.NodeControlData{}).
+			Set("recently-dead", now, report.NodeControlData{Dead: true})
+	)
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("%s", test.Diff(want, have))
+	}
+	if have := report.MakeNodeControlDataLatestMap().PruneDead(cutoff); have.Size() != 0 {
+		t.Errorf("expected empty map, got %v", have)
+	}
+}
+
+func TestControlsObserveMetrics(t *testing.T) {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "controls"}, []string{"topology", "category"})
+	report.Controls{
+		"start":   {ID: "start", Category: "lifecycle"},
+		"stop":    {ID: "stop", Category: "lifecycle"},
+		"exec":    {ID: "exec"},
+		"pause":   {ID: "pause", Category: "lifecycle"},
+		"restart": {ID: "restart", Category: "lifecycle"},
+	}.ObserveMetrics(gauge, "containers")
+
+	for category, want := range map[string]float64{"lifecycle": 4, "": 1} {
+		var m dto.Metric
+		if err := gauge.WithLabelValues("containers", category).Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		if have := m.GetGauge().GetValue(); want != have {
+			t.Errorf("category %q: want %v, have %v", category, want, have)
+		}
+	}
+
+	// The last lifecycle control goes: its category must not stay at 4.
+	report.Controls{"exec": {ID: "exec"}}.ObserveMetrics(gauge, "containers")
+	report.Controls{"attach": {ID: "attach", Category: "debug"}}.ObserveMetrics(gauge, "pods")
+	if gauge.DeleteLabelValues("containers", "lifecycle") {
+		t.Error("expected the lifecycle category to have been removed")
+	}
+	for labels, want := range map[[2]string]float64{
+		{"containers", ""}: 1,
+		{"pods", "debug"}:  1,
+	} {
+		var m dto.Metric
+		if err := gauge.WithLabelValues(labels[0], labels[1]).Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		if have := m.GetGauge().GetValue(); want != have {
+			t.Errorf("%v: want %v, have %v", labels, want, have)
+		}
+	}
+	// Other topologies are left alone.
+	report.Controls{}.ObserveMetrics(gauge, "hosts")
+	var m dto.Metric
+	if err := gauge.WithLabelValues("pods", "debug").Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	if have := m.GetGauge().GetValue(); have != 1 {
+		t.Errorf("pods/debug: want 1, have %v", have)
+	}
+}
+
+func TestControlsTree(t *testing.T) {
+	var (
+		logs     = report.Control{ID: "logs", Human: "Logs", Rank: 2}
+		tail     = report.Control{ID: "tail", Human: "Tail", Rank: 2, ParentID: "logs"}
+		download = report.Control{ID: "download", Human: "Download", Rank: 1, ParentID: "logs"}
+		search   = report.Control{ID: "search", Human: "Search", Rank: 3, ParentID: "logs"}
+		stop     = report.Control{ID: "stop", Human: "Stop", Rank: 1}
+		orphan   = report.Control{ID: "orphan", Human: "Orphan", Rank: 3, ParentID: "missing"}
+		self     = report.Control{ID: "self", Human: "Self", Rank: 4, ParentID: "self"}
+		cycleA   = report.Control{ID: "a", Human: "A", Rank: 5, ParentID: "b"}
+		cycleB   = report.Control{ID: "b", Human: "B", Rank: 6, ParentID: "a"}
+		offCycle = report.Control{ID: "x", Human: "X", Rank: 1, ParentID: "a"}
+	)
+	for _, tc := range []struct {
+		name     string
+		controls report.Controls
+		want     []report.ControlNode
+	}{
+		{"empty", report.Controls{}, []report.ControlNode{}},
+		{
+			"two levels",
+			report.Controls{"logs": logs, "tail": tail, "download": download, "search": search, "stop": stop},
+			[]report.ControlNode{
+				{Control: stop},
+				{Control: logs, Children: []report.ControlNode{{Control: download}, {Control: tail}, {Control: search}}},
+			},
+		},
+		{
+			"orphans",
+			report.Controls{"stop": stop, "orphan": orphan},
+			[]report.ControlNode{{Control: stop}, {Control: orphan}},
+		},
+		{
+			"cycles",
+			report.Controls{"stop": stop, "self": self, "a": cycleA, "b": cycleB},
+			[]report.ControlNode{
+				{Control: stop},
+				{Control: self},
+				{Control: cycleA, Children: []report.ControlNode{{Control: cycleB}}},
+			},
+		},
+		{
+			"hanging off a cycle",
+			report.Controls{"x": offCycle, "a": cycleA, "b": cycleB},
+			[]report.ControlNode{
+				{Control: cycleA, Children: []report.ControlNode{{Control: offCycle}, {Control: cycleB}}},
+			},
+		},
+	} {
+		if have := tc.controls.Tree(); !reflect.DeepEqual(tc.want, have) {
+			t.Errorf("%s: %s", tc.name, test.Diff(tc.want, have))
+		}
+	}
+}
+
+func TestMakeControls(t *testing.T) {
+	for _, controls := range []report.Controls{report.MakeControls(), report.MakeControlsWithCapacity(10)} {
+		if controls == nil || len(controls) != 0 {
+			t.Errorf("expected non-nil empty controls, got %#v", controls)
+		}
+	}
+}
+
+func BenchmarkControlsAddControls(b *testing.B) {
+	controls := makeBenchmarkControls(0, 1000).Sorted()
+	for _, bm := range []struct {
+		name string
+		make func() report.Controls
+	}{
+		{"literal", func() report.Controls { return report.Controls{} }},
+		{"capacity", func() report.Controls { return report.MakeControlsWithCapacity(len(controls)) }},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				result := bm.make()
+				result.AddControls(controls)
+				benchmarkControlsResult = result
+			}
+		})
+	}
+}
+
+// Encodes with a single encoder, as when encoding a whole report.
+func BenchmarkNodeControlsEncodeReused(b *testing.B) {
+	// Baseline (MsgPack, linux/amd64), before pooling wireNodeControls:
+	//   509ns/op   128B/op   3 allocs/op
+	// after:
+	//   393ns/op    32B/op   1 allocs/op
+	nc := makeBenchmarkNodeControls(10)
+	buf := &bytes.Buffer{}
+	encoder := codec.NewEncoder(buf, &codec.MsgpackHandle{})
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		encoder.Encode(&nc)
+	}
+}
+
+func TestControlsCopyInto(t *testing.T) {
+	var (
+		controls = report.Controls{"start": {ID: "start", Human: "Start"}, "stop": {ID: "stop", Human: "Stop"}}
+		dst      = report.Controls{"stop": {ID: "stop", Human: "Old stop"}, "exec": {ID: "exec", Human: "Exec"}}
+		want     = report.Controls{"start": controls["start"], "stop": controls["stop"], "exec": dst["exec"]}
+	)
+	controls.CopyInto(dst)
+	if !reflect.DeepEqual(want, dst) {
+		t.Error(test.Diff(want, dst))
+	}
+
+	if have := (report.Controls{}).Copy(); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty controls, got %#v", have)
+	}
+	if have := report.Controls(nil).Copy(); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty controls, got %#v", have)
+	}
+}
+
+func BenchmarkControlsCopy(b *testing.B) {
+	controls := makeBenchmarkControls(0, 100)
+	b.Run("Copy", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			benchmarkControlsResult = controls.Copy()
+		}
+	})
+	b.Run("CopyInto", func(b *testing.B) {
+		dst := report.MakeControlsWithCapacity(len(controls))
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for k := range dst {
+				delete(dst, k)
+			}
+			controls.CopyInto(dst)
+		}
+		benchmarkControlsResult = dst
+	})
+}
@@ -0,0 +1,76 @@
+package report
+
+// fontAwesome5Icons maps Font Awesome 4 icon names which were renamed in
+// Font Awesome 5 to their new names. Icons missing here kept their name.
+// See https://fontawesome.com/how-to-use/on-the-web/setup/upgrading-from-version-4
+var fontAwesome5Icons = map[string]string{
+	"fa-area-chart":     "fa-chart-area",
+	"fa-arrows":         "fa-arrows-alt",
+	"fa-arrows-h":       "fa-arrows-alt-h",
+	"fa-arrows-v":       "fa-arrows-alt-v",
+	"fa-bar-chart":      "fa-chart-bar",
+	"fa-chain-broken":   "fa-unlink",
+	"fa-check-circle-o": "fa-check-circle",
+	"fa-circle-o":       "fa-circle",
+	"fa-clock-o":        "fa-clock",
+	"fa-close":          "fa-times",
+	"fa-cloud-download": "fa-cloud-download-alt",
+	"fa-cloud-upload":   "fa-cloud-upload-alt",
+	"fa-dashboard":      "fa-tachometer-alt",
+	"fa-exchange":       "fa-exchange-alt",
+	"fa-external-link":  "fa-external-link-alt",
+	"fa-file-o":         "fa-file",
+	"fa-file-text-o":    "fa-file-alt",
+	"fa-floppy-o":       "fa-save",
+	"fa-gear":           "fa-cog",
+	"fa-hdd-o":          "fa-hdd",
+	"fa-line-chart":     "fa-chart-line",
+	"fa-pause-circle-o": "fa-pause-circle",
+	"fa-pencil":         "fa-pencil-alt",
+	"fa-picture-o":      "fa-image",
+	"fa-pie-chart":      "fa-chart-pie",
+	"fa-play-circle-o":  "fa-play-circle",
+	"fa-refresh":        "fa-sync",
+	"fa-remove":         "fa-times",
+	"fa-repeat":         "fa-redo",
+	"fa-rotate-left":    "fa-undo",
+	"fa-rotate-right":   "fa-redo",
+	"fa-sign-in":        "fa-sign-in-alt",
+	"fa-sign-out":       "fa-sign-out-alt",
+	"fa-square-o":       "fa-square",
+	"fa-stop-circle-o":  "fa-stop-circle",
+	"fa-tachometer":     "fa-tachometer-alt",
+	"fa-times-circle-o": "fa-times-circle",
+	"fa-trash-o":        "fa-trash-alt",
+	"fa-warning":        "fa-exclamation-triangle",
+}
+
+// fontAwesome5Renamed holds the new names in fontAwesome5Icons, which
+// IsValidIcon accepts alongside the Font Awesome 4 ones so that migrated
+// controls still validate.
+var fontAwesome5Renamed = func() map[string]struct{} {
+	result := make(map[string]struct{}, len(fontAwesome5Icons))
+	for _, icon := range fontAwesome5Icons {
+		result[icon] = struct{}{}
+	}
+	return result
+}()
+
+// MigrateIcon returns c with its Icon renamed from Font Awesome 4 to Font
+// Awesome 5, if it was renamed. This keeps old probes working with the new
+// UI.
+func (c Control) MigrateIcon() Control {
+	if icon, ok := fontAwesome5Icons[c.Icon]; ok {
+		c.Icon = icon
+	}
+	return c
+}
+
+// MigrateIcons returns a copy of cs with MigrateIcon applied to each control.
+func (cs Controls) MigrateIcons() Controls {
+	result := MakeControlsWithCapacity(len(cs))
+	for k, c := range cs {
+		result[k] = c.MigrateIcon()
+	}
+	return result
+}
@@ -1,5 +1,5 @@
 // Generated file, do not edit.
-// To regenerate, run ../extras/generate_latest_map ./latest_map_generated.go string NodeControlData
+// To regenerate, run ../extras/generate_latest_map ./latest_map_generated.go string NodeControlData ControlResult ControlProgress ControlAudit
 
 package report
 
@@ -445,3 +445,654 @@ func (NodeControlDataLatestMap) MarshalJSON() ([]byte, error) {
 func (*NodeControlDataLatestMap) UnmarshalJSON(b []byte) error {
 	panic("UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead")
 }
+
+type controlResultLatestEntry struct {
+	key       string
+	Timestamp time.Time     `json:"timestamp"`
+	Value     ControlResult `json:"value"`
+	dummySelfer
+}
+
+// String returns the StringLatestEntry's string representation.
+func (e *controlResultLatestEntry) String() string {
+	return fmt.Sprintf("%v (%s)", e.Value, e.Timestamp.String())
+}
+
+// Equal returns true if the supplied StringLatestEntry is equal to this one.
+func (e *controlResultLatestEntry) Equal(e2 *controlResultLatestEntry) bool {
+	return e.Timestamp.Equal(e2.Timestamp) && e.Value == e2.Value
+}
+
+// ControlResultLatestMap holds latest ControlResult instances, as a slice sorted by key.
+type ControlResultLatestMap struct{ entries []controlResultLatestEntry }
+
+// MakeControlResultLatestMap makes an empty ControlResultLatestMap.
+func MakeControlResultLatestMap() ControlResultLatestMap {
+	return ControlResultLatestMap{}
+}
+
+// Size returns the number of elements.
+func (m ControlResultLatestMap) Size() int {
+	return len(m.entries)
+}
+
+// Merge produces a fresh ControlResultLatestMap containing the keys from both inputs.
+// When both inputs contain the same key, the newer value is used.
+func (m ControlResultLatestMap) Merge(n ControlResultLatestMap) ControlResultLatestMap {
+	switch {
+	case m.entries == nil:
+		return n
+	case n.entries == nil:
+		return m
+	}
+	out := make([]controlResultLatestEntry, 0, len(m.entries)+len(n.entries))
+
+	i, j := 0, 0
+	for i < len(m.entries) {
+		switch {
+		case j >= len(n.entries) || m.entries[i].key < n.entries[j].key:
+			out = append(out, m.entries[i])
+			i++
+		case m.entries[i].key == n.entries[j].key:
+			if m.entries[i].Timestamp.Before(n.entries[j].Timestamp) {
+				out = append(out, n.entries[j])
+			} else {
+				out = append(out, m.entries[i])
+			}
+			i++
+			j++
+		default:
+			out = append(out, n.entries[j])
+			j++
+		}
+	}
+	out = append(out, n.entries[j:]...)
+	return ControlResultLatestMap{out}
+}
+
+// Lookup the value for the given key.
+func (m ControlResultLatestMap) Lookup(key string) (ControlResult, bool) {
+	v, _, ok := m.LookupEntry(key)
+	if !ok {
+		var zero ControlResult
+		return zero, false
+	}
+	return v, true
+}
+
+// LookupEntry returns the raw entry for the given key.
+func (m ControlResultLatestMap) LookupEntry(key string) (ControlResult, time.Time, bool) {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	if i < len(m.entries) && m.entries[i].key == key {
+		return m.entries[i].Value, m.entries[i].Timestamp, true
+	}
+	var zero ControlResult
+	return zero, time.Time{}, false
+}
+
+// locate the position where key should go, and make room for it if not there already
+func (m *ControlResultLatestMap) locate(key string) int {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	// i is now the position where key should go, either at the end or in the middle
+	if i == len(m.entries) || m.entries[i].key != key {
+		m.entries = append(m.entries, controlResultLatestEntry{})
+		copy(m.entries[i+1:], m.entries[i:])
+	}
+	return i
+}
+
+// Set the value for the given key.
+func (m ControlResultLatestMap) Set(key string, timestamp time.Time, value ControlResult) ControlResultLatestMap {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	// i is now the position where key should go, either at the end or in the middle
+	oldEntries := m.entries
+	if i == len(m.entries) {
+		m.entries = make([]controlResultLatestEntry, len(oldEntries)+1)
+		copy(m.entries, oldEntries)
+	} else if m.entries[i].key == key {
+		m.entries = make([]controlResultLatestEntry, len(oldEntries))
+		copy(m.entries, oldEntries)
+	} else {
+		m.entries = make([]controlResultLatestEntry, len(oldEntries)+1)
+		copy(m.entries, oldEntries[:i])
+		copy(m.entries[i+1:], oldEntries[i:])
+	}
+	m.entries[i] = controlResultLatestEntry{key: key, Timestamp: timestamp, Value: value}
+	return m
+}
+
+// ForEach executes fn on each key value pair in the map.
+func (m ControlResultLatestMap) ForEach(fn func(k string, timestamp time.Time, v ControlResult)) {
+	for _, value := range m.entries {
+		fn(value.key, value.Timestamp, value.Value)
+	}
+}
+
+// String returns the ControlResultLatestMap's string representation.
+func (m ControlResultLatestMap) String() string {
+	buf := bytes.NewBufferString("{")
+	for _, val := range m.entries {
+		fmt.Fprintf(buf, "%s: %s,\n", val.key, val)
+	}
+	fmt.Fprintf(buf, "}")
+	return buf.String()
+}
+
+// DeepEqual tests equality with other ControlResultLatestMap.
+func (m ControlResultLatestMap) DeepEqual(n ControlResultLatestMap) bool {
+	if m.Size() != n.Size() {
+		return false
+	}
+	for i := range m.entries {
+		if m.entries[i].key != n.entries[i].key || !m.entries[i].Equal(&n.entries[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// CodecEncodeSelf implements codec.Selfer.
+// Duplicates the output for a built-in map without generating an
+// intermediate copy of the data structure, to save time.  Note this
+// means we are using undocumented, internal APIs, which could break
+// in the future.  See https://github.com/weaveworks/scope/pull/1709
+// for more information.
+func (m *ControlResultLatestMap) CodecEncodeSelf(encoder *codec.Encoder) {
+	z, r := codec.GenHelperEncoder(encoder)
+	if m.entries == nil {
+		r.EncodeNil()
+		return
+	}
+	r.EncodeMapStart(m.Size())
+	for _, val := range m.entries {
+		z.EncSendContainerState(containerMapKey)
+		r.EncodeString(cUTF8, val.key)
+		z.EncSendContainerState(containerMapValue)
+		val.CodecEncodeSelf(encoder)
+	}
+	z.EncSendContainerState(containerMapEnd)
+}
+
+// CodecDecodeSelf implements codec.Selfer.
+// Decodes the input as for a built-in map, without creating an
+// intermediate copy of the data structure to save time. Uses
+// undocumented, internal APIs as for CodecEncodeSelf.
+func (m *ControlResultLatestMap) CodecDecodeSelf(decoder *codec.Decoder) {
+	m.entries = nil
+	z, r := codec.GenHelperDecoder(decoder)
+	if r.TryDecodeAsNil() {
+		return
+	}
+
+	length := r.ReadMapStart()
+	if length > 0 {
+		m.entries = make([]controlResultLatestEntry, 0, length)
+	}
+	for i := 0; length < 0 || i < length; i++ {
+		if length < 0 && r.CheckBreak() {
+			break
+		}
+		z.DecSendContainerState(containerMapKey)
+		var key string
+		if !r.TryDecodeAsNil() {
+			key = r.DecodeString()
+		}
+		i := m.locate(key)
+		m.entries[i].key = key
+		z.DecSendContainerState(containerMapValue)
+		if !r.TryDecodeAsNil() {
+			m.entries[i].CodecDecodeSelf(decoder)
+		}
+	}
+	z.DecSendContainerState(containerMapEnd)
+}
+
+// MarshalJSON shouldn't be used, use CodecEncodeSelf instead.
+func (ControlResultLatestMap) MarshalJSON() ([]byte, error) {
+	panic("MarshalJSON shouldn't be used, use CodecEncodeSelf instead")
+}
+
+// UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead.
+func (*ControlResultLatestMap) UnmarshalJSON(b []byte) error {
+	panic("UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead")
+}
+
+type controlProgressLatestEntry struct {
+	key       string
+	Timestamp time.Time       `json:"timestamp"`
+	Value     ControlProgress `json:"value"`
+	dummySelfer
+}
+
+// String returns the StringLatestEntry's string representation.
+func (e *controlProgressLatestEntry) String() string {
+	return fmt.Sprintf("%v (%s)", e.Value, e.Timestamp.String())
+}
+
+// Equal returns true if the supplied StringLatestEntry is equal to this one.
+func (e *controlProgressLatestEntry) Equal(e2 *controlProgressLatestEntry) bool {
+	return e.Timestamp.Equal(e2.Timestamp) && e.Value == e2.Value
+}
+
+// ControlProgressLatestMap holds latest ControlProgress instances, as a slice sorted by key.
+type ControlProgressLatestMap struct{ entries []controlProgressLatestEntry }
+
+// MakeControlProgressLatestMap makes an empty ControlProgressLatestMap.
+func MakeControlProgressLatestMap() ControlProgressLatestMap {
+	return ControlProgressLatestMap{}
+}
+
+// Size returns the number of elements.
+func (m ControlProgressLatestMap) Size() int {
+	return len(m.entries)
+}
+
+// Merge produces a fresh ControlProgressLatestMap containing the keys from both inputs.
+// When both inputs contain the same key, the newer value is used.
+func (m ControlProgressLatestMap) Merge(n ControlProgressLatestMap) ControlProgressLatestMap {
+	switch {
+	case m.entries == nil:
+		return n
+	case n.entries == nil:
+		return m
+	}
+	out := make([]controlProgressLatestEntry, 0, len(m.entries)+len(n.entries))
+
+	i, j := 0, 0
+	for i < len(m.entries) {
+		switch {
+		case j >= len(n.entries) || m.entries[i].key < n.entries[j].key:
+			out = append(out, m.entries[i])
+			i++
+		case m.entries[i].key == n.entries[j].key:
+			if m.entries[i].Timestamp.Before(n.entries[j].Timestamp) {
+				out = append(out, n.entries[j])
+			} else {
+				out = append(out, m.entries[i])
+			}
+			i++
+			j++
+		default:
+			out = append(out, n.entries[j])
+			j++
+		}
+	}
+	out = append(out, n.entries[j:]...)
+	return ControlProgressLatestMap{out}
+}
+
+// Lookup the value for the given key.
+func (m ControlProgressLatestMap) Lookup(key string) (ControlProgress, bool) {
+	v, _, ok := m.LookupEntry(key)
+	if !ok {
+		var zero ControlProgress
+		return zero, false
+	}
+	return v, true
+}
+
+// LookupEntry returns the raw entry for the given key.
+func (m ControlProgressLatestMap) LookupEntry(key string) (ControlProgress, time.Time, bool) {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	if i < len(m.entries) && m.entries[i].key == key {
+		return m.entries[i].Value, m.entries[i].Timestamp, true
+	}
+	var zero ControlProgress
+	return zero, time.Time{}, false
+}
+
+// locate the position where key should go, and make room for it if not there already
+func (m *ControlProgressLatestMap) locate(key string) int {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	// i is now the position where key should go, either at the end or in the middle
+	if i == len(m.entries) || m.entries[i].key != key {
+		m.entries = append(m.entries, controlProgressLatestEntry{})
+		copy(m.entries[i+1:], m.entries[i:])
+	}
+	return i
+}
+
+// Set the value for the given key.
+func (m ControlProgressLatestMap) Set(key string, timestamp time.Time, value ControlProgress) ControlProgressLatestMap {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	// i is now the position where key should go, either at the end or in the middle
+	oldEntries := m.entries
+	if i == len(m.entries) {
+		m.entries = make([]controlProgressLatestEntry, len(oldEntries)+1)
+		copy(m.entries, oldEntries)
+	} else if m.entries[i].key == key {
+		m.entries = make([]controlProgressLatestEntry, len(oldEntries))
+		copy(m.entries, oldEntries)
+	} else {
+		m.entries = make([]controlProgressLatestEntry, len(oldEntries)+1)
+		copy(m.entries, oldEntries[:i])
+		copy(m.entries[i+1:], oldEntries[i:])
+	}
+	m.entries[i] = controlProgressLatestEntry{key: key, Timestamp: timestamp, Value: value}
+	return m
+}
+
+// ForEach executes fn on each key value pair in the map.
+func (m ControlProgressLatestMap) ForEach(fn func(k string, timestamp time.Time, v ControlProgress)) {
+	for _, value := range m.entries {
+		fn(value.key, value.Timestamp, value.Value)
+	}
+}
+
+// String returns the ControlProgressLatestMap's string representation.
+func (m ControlProgressLatestMap) String() string {
+	buf := bytes.NewBufferString("{")
+	for _, val := range m.entries {
+		fmt.Fprintf(buf, "%s: %s,\n", val.key, val)
+	}
+	fmt.Fprintf(buf, "}")
+	return buf.String()
+}
+
+// DeepEqual tests equality with other ControlProgressLatestMap.
+func (m ControlProgressLatestMap) DeepEqual(n ControlProgressLatestMap) bool {
+	if m.Size() != n.Size() {
+		return false
+	}
+	for i := range m.entries {
+		if m.entries[i].key != n.entries[i].key || !m.entries[i].Equal(&n.entries[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// CodecEncodeSelf implements codec.Selfer.
+// Duplicates the output for a built-in map without generating an
+// intermediate copy of the data structure, to save time.  Note this
+// means we are using undocumented, internal APIs, which could break
+// in the future.  See https://github.com/weaveworks/scope/pull/1709
+// for more information.
+func (m *ControlProgressLatestMap) CodecEncodeSelf(encoder *codec.Encoder) {
+	z, r := codec.GenHelperEncoder(encoder)
+	if m.entries == nil {
+		r.EncodeNil()
+		return
+	}
+	r.EncodeMapStart(m.Size())
+	for _, val := range m.entries {
+		z.EncSendContainerState(containerMapKey)
+		r.EncodeString(cUTF8, val.key)
+		z.EncSendContainerState(containerMapValue)
+		val.CodecEncodeSelf(encoder)
+	}
+	z.EncSendContainerState(containerMapEnd)
+}
+
+// CodecDecodeSelf implements codec.Selfer.
+// Decodes the input as for a built-in map, without creating an
+// intermediate copy of the data structure to save time. Uses
+// undocumented, internal APIs as for CodecEncodeSelf.
+func (m *ControlProgressLatestMap) CodecDecodeSelf(decoder *codec.Decoder) {
+	m.entries = nil
+	z, r := codec.GenHelperDecoder(decoder)
+	if r.TryDecodeAsNil() {
+		return
+	}
+
+	length := r.ReadMapStart()
+	if length > 0 {
+		m.entries = make([]controlProgressLatestEntry, 0, length)
+	}
+	for i := 0; length < 0 || i < length; i++ {
+		if length < 0 && r.CheckBreak() {
+			break
+		}
+		z.DecSendContainerState(containerMapKey)
+		var key string
+		if !r.TryDecodeAsNil() {
+			key = r.DecodeString()
+		}
+		i := m.locate(key)
+		m.entries[i].key = key
+		z.DecSendContainerState(containerMapValue)
+		if !r.TryDecodeAsNil() {
+			m.entries[i].CodecDecodeSelf(decoder)
+		}
+	}
+	z.DecSendContainerState(containerMapEnd)
+}
+
+// MarshalJSON shouldn't be used, use CodecEncodeSelf instead.
+func (ControlProgressLatestMap) MarshalJSON() ([]byte, error) {
+	panic("MarshalJSON shouldn't be used, use CodecEncodeSelf instead")
+}
+
+// UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead.
+func (*ControlProgressLatestMap) UnmarshalJSON(b []byte) error {
+	panic("UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead")
+}
+
+type controlAuditLatestEntry struct {
+	key       string
+	Timestamp time.Time    `json:"timestamp"`
+	Value     ControlAudit `json:"value"`
+	dummySelfer
+}
+
+// String returns the StringLatestEntry's string representation.
+func (e *controlAuditLatestEntry) String() string {
+	return fmt.Sprintf("%v (%s)", e.Value, e.Timestamp.String())
+}
+
+// Equal returns true if the supplied StringLatestEntry is equal to this one.
+func (e *controlAuditLatestEntry) Equal(e2 *controlAuditLatestEntry) bool {
+	return e.Timestamp.Equal(e2.Timestamp) && e.Value == e2.Value
+}
+
+// ControlAuditLatestMap holds latest ControlAudit instances, as a slice sorted by key.
+type ControlAuditLatestMap struct{ entries []controlAuditLatestEntry }
+
+// MakeControlAuditLatestMap makes an empty ControlAuditLatestMap.
+func MakeControlAuditLatestMap() ControlAuditLatestMap {
+	return ControlAuditLatestMap{}
+}
+
+// Size returns the number of elements.
+func (m ControlAuditLatestMap) Size() int {
+	return len(m.entries)
+}
+
+// Merge produces a fresh ControlAuditLatestMap containing the keys from both inputs.
+// When both inputs contain the same key, the newer value is used.
+func (m ControlAuditLatestMap) Merge(n ControlAuditLatestMap) ControlAuditLatestMap {
+	switch {
+	case m.entries == nil:
+		return n
+	case n.entries == nil:
+		return m
+	}
+	out := make([]controlAuditLatestEntry, 0, len(m.entries)+len(n.entries))
+
+	i, j := 0, 0
+	for i < len(m.entries) {
+		switch {
+		case j >= len(n.entries) || m.entries[i].key < n.entries[j].key:
+			out = append(out, m.entries[i])
+			i++
+		case m.entries[i].key == n.entries[j].key:
+			if m.entries[i].Timestamp.Before(n.entries[j].Timestamp) {
+				out = append(out, n.entries[j])
+			} else {
+				out = append(out, m.entries[i])
+			}
+			i++
+			j++
+		default:
+			out = append(out, n.entries[j])
+			j++
+		}
+	}
+	out = append(out, n.entries[j:]...)
+	return ControlAuditLatestMap{out}
+}
+
+// Lookup the value for the given key.
+func (m ControlAuditLatestMap) Lookup(key string) (ControlAudit, bool) {
+	v, _, ok := m.LookupEntry(key)
+	if !ok {
+		var zero ControlAudit
+		return zero, false
+	}
+	return v, true
+}
+
+// LookupEntry returns the raw entry for the given key.
+func (m ControlAuditLatestMap) LookupEntry(key string) (ControlAudit, time.Time, bool) {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	if i < len(m.entries) && m.entries[i].key == key {
+		return m.entries[i].Value, m.entries[i].Timestamp, true
+	}
+	var zero ControlAudit
+	return zero, time.Time{}, false
+}
+
+// locate the position where key should go, and make room for it if not there already
+func (m *ControlAuditLatestMap) locate(key string) int {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	// i is now the position where key should go, either at the end or in the middle
+	if i == len(m.entries) || m.entries[i].key != key {
+		m.entries = append(m.entries, controlAuditLatestEntry{})
+		copy(m.entries[i+1:], m.entries[i:])
+	}
+	return i
+}
+
+// Set the value for the given key.
+func (m ControlAuditLatestMap) Set(key string, timestamp time.Time, value ControlAudit) ControlAuditLatestMap {
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].key >= key
+	})
+	// i is now the position where key should go, either at the end or in the middle
+	oldEntries := m.entries
+	if i == len(m.entries) {
+		m.entries = make([]controlAuditLatestEntry, len(oldEntries)+1)
+		copy(m.entries, oldEntries)
+	} else if m.entries[i].key == key {
+		m.entries = make([]controlAuditLatestEntry, len(oldEntries))
+		copy(m.entries, oldEntries)
+	} else {
+		m.entries = make([]controlAuditLatestEntry, len(oldEntries)+1)
+		copy(m.entries, oldEntries[:i])
+		copy(m.entries[i+1:], oldEntries[i:])
+	}
+	m.entries[i] = controlAuditLatestEntry{key: key, Timestamp: timestamp, Value: value}
+	return m
+}
+
+// ForEach executes fn on each key value pair in the map.
+func (m ControlAuditLatestMap) ForEach(fn func(k string, timestamp time.Time, v ControlAudit)) {
+	for _, value := range m.entries {
+		fn(value.key, value.Timestamp, value.Value)
+	}
+}
+
+// String returns the ControlAuditLatestMap's string representation.
+func (m ControlAuditLatestMap) String() string {
+	buf := bytes.NewBufferString("{")
+	for _, val := range m.entries {
+		fmt.Fprintf(buf, "%s: %s,\n", val.key, val)
+	}
+	fmt.Fprintf(buf, "}")
+	return buf.String()
+}
+
+// DeepEqual tests equality with other ControlAuditLatestMap.
+func (m ControlAuditLatestMap) DeepEqual(n ControlAuditLatestMap) bool {
+	if m.Size() != n.Size() {
+		return false
+	}
+	for i := range m.entries {
+		if m.entries[i].key != n.entries[i].key || !m.entries[i].Equal(&n.entries[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// CodecEncodeSelf implements codec.Selfer.
+// Duplicates the output for a built-in map without generating an
+// intermediate copy of the data structure, to save time.  Note this
+// means we are using undocumented, internal APIs, which could break
+// in the future.  See https://github.com/weaveworks/scope/pull/1709
+// for more information.
+func (m *ControlAuditLatestMap) CodecEncodeSelf(encoder *codec.Encoder) {
+	z, r := codec.GenHelperEncoder(encoder)
+	if m.entries == nil {
+		r.EncodeNil()
+		return
+	}
+	r.EncodeMapStart(m.Size())
+	for _, val := range m.entries {
+		z.EncSendContainerState(containerMapKey)
+		r.EncodeString(cUTF8, val.key)
+		z.EncSendContainerState(containerMapValue)
+		val.CodecEncodeSelf(encoder)
+	}
+	z.EncSendContainerState(containerMapEnd)
+}
+
+// CodecDecodeSelf implements codec.Selfer.
+// Decodes the input as for a built-in map, without creating an
+// intermediate copy of the data structure to save time. Uses
+// undocumented, internal APIs as for CodecEncodeSelf.
+func (m *ControlAuditLatestMap) CodecDecodeSelf(decoder *codec.Decoder) {
+	m.entries = nil
+	z, r := codec.GenHelperDecoder(decoder)
+	if r.TryDecodeAsNil() {
+		return
+	}
+
+	length := r.ReadMapStart()
+	if length > 0 {
+		m.entries = make([]controlAuditLatestEntry, 0, length)
+	}
+	for i := 0; length < 0 || i < length; i++ {
+		if length < 0 && r.CheckBreak() {
+			break
+		}
+		z.DecSendContainerState(containerMapKey)
+		var key string
+		if !r.TryDecodeAsNil() {
+			key = r.DecodeString()
+		}
+		i := m.locate(key)
+		m.entries[i].key = key
+		z.DecSendContainerState(containerMapValue)
+		if !r.TryDecodeAsNil() {
+			m.entries[i].CodecDecodeSelf(decoder)
+		}
+	}
+	z.DecSendContainerState(containerMapEnd)
+}
+
+// MarshalJSON shouldn't be used, use CodecEncodeSelf instead.
+func (ControlAuditLatestMap) MarshalJSON() ([]byte, error) {
+	panic("MarshalJSON shouldn't be used, use CodecEncodeSelf instead")
+}
+
+// UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead.
+func (*ControlAuditLatestMap) UnmarshalJSON(b []byte) error {
+	panic("UnmarshalJSON shouldn't be used, use CodecDecodeSelf instead")
+}
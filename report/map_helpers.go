@@ -83,9 +83,11 @@ func mapKeys(m ps.Map) []string {
 
 // constants from https://github.com/ugorji/go/blob/master/codec/helper.go#L207
 const (
-	containerMapKey   = 2
-	containerMapValue = 3
-	containerMapEnd   = 4
+	containerMapKey    = 2
+	containerMapValue  = 3
+	containerMapEnd    = 4
+	containerArrayElem = 6
+	containerArrayEnd  = 7
 	// from https://github.com/ugorji/go/blob/master/codec/helper.go#L152
 	cUTF8 = 2
 )
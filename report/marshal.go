@@ -26,6 +26,25 @@ func (s *dummySelfer) CodecEncodeSelf(encoder *codec.Encoder) {
 	panic("This shouldn't happen: perhaps something has gone wrong in code generation?")
 }
 
+// StrictHandle returns a copy of h which fails to decode maps containing
+// keys the target struct doesn't know about, rather than skipping them. This
+// is meant for tests and staging, to catch drift between probe and app
+// versions early; production should keep using lenient handles. Handles
+// other than msgpack and JSON are returned unchanged.
+func StrictHandle(h codec.Handle) codec.Handle {
+	switch h := h.(type) {
+	case *codec.MsgpackHandle:
+		strict := *h
+		strict.ErrorIfNoField = true
+		return &strict
+	case *codec.JsonHandle:
+		strict := *h
+		strict.ErrorIfNoField = true
+		return &strict
+	}
+	return h
+}
+
 // WriteBinary writes a Report as a gzipped msgpack.
 func (rep Report) WriteBinary(w io.Writer, compressionLevel int) error {
 	gzwriter, err := gzip.NewWriterLevel(w, compressionLevel)
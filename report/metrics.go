@@ -230,13 +230,16 @@ type WireMetrics struct {
 	dummySelfer
 }
 
+// renderTime formats t as RFC3339Nano in UTC, so it round-trips through
+// parseTime exactly. The zero time is rendered as "".
 func renderTime(t time.Time) string {
 	if t.IsZero() {
 		return ""
 	}
-	return t.Format(time.RFC3339Nano)
+	return t.UTC().Format(time.RFC3339Nano)
 }
 
+// parseTime is the inverse of renderTime; "" is the zero time.
 func parseTime(s string) time.Time {
 	if s == "" {
 		return time.Time{}
@@ -27,3 +27,29 @@ func TestFirstLast(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderParseTime(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	for _, want := range []time.Time{
+		time.Date(2017, 3, 14, 15, 9, 26, 535897932, time.UTC),
+		time.Date(2017, 3, 14, 15, 9, 26, 1, est),
+		time.Date(2017, 3, 14, 15, 9, 26, 0, time.UTC),
+		time.Now(),
+	} {
+		s := renderTime(want)
+		have := parseTime(s)
+		if !have.Equal(want) {
+			t.Errorf("parseTime(%q) => %v, Expected: %v", s, have, want)
+		}
+		if have.Location() != time.UTC {
+			t.Errorf("parseTime(%q) => %v, Expected UTC", s, have)
+		}
+	}
+
+	if s := renderTime(time.Time{}); s != "" {
+		t.Errorf("renderTime(zero) => %q, Expected: \"\"", s)
+	}
+	if have := parseTime(""); !have.IsZero() {
+		t.Errorf("parseTime(\"\") => %v, Expected zero time", have)
+	}
+}
@@ -0,0 +1,124 @@
+package report
+
+import (
+	"encoding/json"
+
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	defaultNodeControlsHistorySize = 10
+	// maxNodeControlsHistorySize bounds the size of a decoded history, which
+	// comes from its input.
+	maxNodeControlsHistorySize = 1024
+)
+
+// NodeControlsHistory keeps the last few NodeControls states of a node, for
+// debugging controls which flap. It is not part of the report; it exists to
+// be encoded into diagnostic dumps. The zero value is an empty history
+// holding up to 10 entries.
+type NodeControlsHistory struct {
+	entries []NodeControls // ring buffer, cap(entries) is the size
+	next    int            // where the next entry goes, once the ring is full
+}
+
+// NewNodeControlsHistory makes a history holding up to size entries. Sizes
+// smaller than 1 are treated as 1, and sizes larger than 1024 as 1024.
+func NewNodeControlsHistory(size int) *NodeControlsHistory {
+	if size < 1 {
+		size = 1
+	}
+	if size > maxNodeControlsHistorySize {
+		size = maxNodeControlsHistorySize
+	}
+	return &NodeControlsHistory{entries: make([]NodeControls, 0, size)}
+}
+
+// Size returns the maximum number of entries h can hold.
+func (h *NodeControlsHistory) Size() int {
+	if cap(h.entries) == 0 {
+		return defaultNodeControlsHistorySize
+	}
+	return cap(h.entries)
+}
+
+// Push records nc, evicting the oldest entry if h is full.
+func (h *NodeControlsHistory) Push(nc NodeControls) {
+	if cap(h.entries) == 0 {
+		h.entries = make([]NodeControls, 0, defaultNodeControlsHistorySize)
+	}
+	if len(h.entries) < cap(h.entries) {
+		h.entries = append(h.entries, nc)
+		return
+	}
+	h.entries[h.next] = nc
+	h.next = (h.next + 1) % len(h.entries)
+}
+
+// Latest returns the most recently pushed entry, if any.
+func (h *NodeControlsHistory) Latest() (NodeControls, bool) {
+	if len(h.entries) == 0 {
+		return NodeControls{}, false
+	}
+	i := h.next - 1
+	if i < 0 {
+		i = len(h.entries) - 1
+	}
+	return h.entries[i], true
+}
+
+// Entries returns a copy of the entries, oldest first.
+func (h *NodeControlsHistory) Entries() []NodeControls {
+	result := make([]NodeControls, 0, len(h.entries))
+	result = append(result, h.entries[h.next:]...)
+	return append(result, h.entries[:h.next]...)
+}
+
+type wireNodeControlsHistory struct {
+	Size    int            `json:"size"`
+	Entries []NodeControls `json:"entries,omitempty"`
+	dummySelfer
+}
+
+func (h *NodeControlsHistory) toIntermediate() wireNodeControlsHistory {
+	return wireNodeControlsHistory{
+		Size:    h.Size(),
+		Entries: h.Entries(),
+	}
+}
+
+func (h *NodeControlsHistory) fromIntermediate(in wireNodeControlsHistory) {
+	*h = *NewNodeControlsHistory(in.Size)
+	for _, nc := range in.Entries {
+		h.Push(nc)
+	}
+}
+
+// CodecEncodeSelf implements codec.Selfer
+func (h *NodeControlsHistory) CodecEncodeSelf(encoder *codec.Encoder) {
+	encoder.Encode(h.toIntermediate())
+}
+
+// CodecDecodeSelf implements codec.Selfer
+func (h *NodeControlsHistory) CodecDecodeSelf(decoder *codec.Decoder) {
+	in := wireNodeControlsHistory{}
+	in.CodecDecodeSelf(decoder)
+	h.fromIntermediate(in)
+}
+
+// MarshalJSON implements json.Marshaler. Prefer CodecEncodeSelf; this
+// exists for code using encoding/json, and writes the same fields.
+func (h NodeControlsHistory) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.toIntermediate())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Prefer CodecDecodeSelf; this
+// exists for code using encoding/json.
+func (h *NodeControlsHistory) UnmarshalJSON(b []byte) error {
+	in := wireNodeControlsHistory{}
+	if err := json.Unmarshal(b, &in); err != nil {
+		return err
+	}
+	h.fromIntermediate(in)
+	return nil
+}
@@ -0,0 +1,134 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ugorji/go/codec"
+
+	"github.com/weaveworks/common/test"
+	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/reflect"
+)
+
+func makeHistoryEntries(n int) []report.NodeControls {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := []report.NodeControls{}
+	for i := 0; i < n; i++ {
+		result = append(result, report.MakeNodeControls().WithTimestamp(start.Add(time.Duration(i)*time.Second), fmt.Sprintf("control-%d", i)))
+	}
+	return result
+}
+
+func TestNodeControlsHistory(t *testing.T) {
+	entries := makeHistoryEntries(5)
+	h := report.NewNodeControlsHistory(3)
+
+	if _, ok := h.Latest(); ok {
+		t.Error("expected no latest entry in an empty history")
+	}
+	if have := h.Entries(); len(have) != 0 {
+		t.Errorf("expected no entries, got %v", have)
+	}
+
+	for i, nc := range entries {
+		h.Push(nc)
+		if have, ok := h.Latest(); !ok || !reflect.DeepEqual(nc, have) {
+			t.Errorf("after %d pushes: %s", i+1, test.Diff(nc, have))
+		}
+		first := 0
+		if i >= 3 {
+			first = i - 2
+		}
+		if want, have := entries[first:i+1], h.Entries(); !reflect.DeepEqual(want, have) {
+			t.Errorf("after %d pushes: %s", i+1, test.Diff(want, have))
+		}
+	}
+}
+
+func TestNodeControlsHistoryEntriesIsACopy(t *testing.T) {
+	h := report.NewNodeControlsHistory(2)
+	h.Push(makeHistoryEntries(1)[0])
+	h.Entries()[0] = report.MakeNodeControls()
+	if have, _ := h.Latest(); !have.Contains("control-0") {
+		t.Errorf("modifying Entries changed the history: %v", have)
+	}
+}
+
+func TestNodeControlsHistoryEncoding(t *testing.T) {
+	for _, h := range []codec.Handle{
+		codec.Handle(&codec.MsgpackHandle{}),
+		codec.Handle(&codec.JsonHandle{}),
+	} {
+		want := report.NewNodeControlsHistory(3)
+		for _, nc := range makeHistoryEntries(4) {
+			want.Push(nc)
+		}
+		buf := &bytes.Buffer{}
+		if err := codec.NewEncoder(buf, h).Encode(want); err != nil {
+			t.Fatal(err)
+		}
+		have := report.NewNodeControlsHistory(0)
+		if err := codec.NewDecoder(buf, h).Decode(have); err != nil {
+			t.Fatal(err)
+		}
+		if want, have := want.Size(), have.Size(); want != have {
+			t.Errorf("size: want %d, have %d", want, have)
+		}
+		if want, have := want.Entries(), have.Entries(); !reflect.DeepEqual(want, have) {
+			t.Error(test.Diff(want, have))
+		}
+	}
+}
+
+func TestNodeControlsHistoryZeroValue(t *testing.T) {
+	var h report.NodeControlsHistory
+	if have := h.Size(); have != 10 {
+		t.Errorf("expected a zero history to hold 10 entries, got %d", have)
+	}
+	entries := makeHistoryEntries(12)
+	for _, nc := range entries {
+		h.Push(nc)
+	}
+	if want, have := entries[2:], h.Entries(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func TestNodeControlsHistoryDecodeSizeIsCapped(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if err := codec.NewEncoder(buf, &codec.JsonHandle{}).Encode(map[string]int{"size": 1 << 40}); err != nil {
+		t.Fatal(err)
+	}
+	var h report.NodeControlsHistory
+	if err := codec.NewDecoder(buf, &codec.JsonHandle{}).Decode(&h); err != nil {
+		t.Fatal(err)
+	}
+	if have := h.Size(); have != 1024 {
+		t.Errorf("expected the decoded size to be capped at 1024, got %d", have)
+	}
+}
+
+func TestNodeControlsHistoryJSON(t *testing.T) {
+	want := report.NewNodeControlsHistory(3)
+	for _, nc := range makeHistoryEntries(4) {
+		want.Push(nc)
+	}
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have report.NodeControlsHistory
+	if err := json.Unmarshal(b, &have); err != nil {
+		t.Fatal(err)
+	}
+	if want, have := want.Size(), have.Size(); want != have {
+		t.Errorf("size: want %d, have %d", want, have)
+	}
+	if want, have := want.Entries(), have.Entries(); !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
@@ -0,0 +1,46 @@
+// Package reportpb holds the protobuf wire format for parts of the report.
+//
+// The types here are written by hand, not generated: they are the message
+// types of nodecontrols.proto, with the field tags the proto package needs
+// to marshal them. Keep the two in step when changing either.
+package reportpb
+
+import (
+	"github.com/golang/protobuf/proto"
+	google_protobuf "github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// NodeControls mirrors report.NodeControls.
+type NodeControls struct {
+	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	Controls  []string                   `protobuf:"bytes,2,rep,name=controls" json:"controls,omitempty"`
+}
+
+// Reset implements proto.Message
+func (m *NodeControls) Reset() { *m = NodeControls{} }
+
+// String implements proto.Message
+func (m *NodeControls) String() string { return proto.CompactTextString(m) }
+
+// ProtoMessage implements proto.Message
+func (*NodeControls) ProtoMessage() {}
+
+// GetTimestamp returns m's Timestamp, or nil if m is nil.
+func (m *NodeControls) GetTimestamp() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+// GetControls returns m's Controls, or nil if m is nil.
+func (m *NodeControls) GetControls() []string {
+	if m != nil {
+		return m.Controls
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*NodeControls)(nil), "reportpb.NodeControls")
+}
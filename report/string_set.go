@@ -28,9 +28,23 @@ func MakeStringSet(strs ...string) StringSet {
 	return StringSet(result)
 }
 
-// Contains returns true if the string set includes the given string
+// IsEmpty returns true if s has no strings.
+func (s StringSet) IsEmpty() bool {
+	return len(s) == 0
+}
+
+// First returns the smallest string in s, and false if s is empty.
+func (s StringSet) First() (string, bool) {
+	if len(s) == 0 {
+		return "", false
+	}
+	return s[0], true
+}
+
+// Contains returns true if the string set includes the given string. It
+// relies on the set being sorted to do a binary search.
 func (s StringSet) Contains(str string) bool {
-	i := sort.Search(len(s), func(i int) bool { return s[i] >= str })
+	i := sort.SearchStrings(s, str)
 	return i < len(s) && s[i] == str
 }
 
@@ -50,6 +64,87 @@ func (s StringSet) Intersection(b StringSet) StringSet {
 	return result
 }
 
+// Difference returns the elements of s which are not in b
+func (s StringSet) Difference(b StringSet) StringSet {
+	result, i, j := emptyStringSet, 0, 0
+	for i < len(s) {
+		switch {
+		case j >= len(b) || s[i] < b[j]:
+			result = append(result, s[i])
+			i++
+		case s[i] > b[j]:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// SymmetricDifference returns the elements which are in exactly one of s
+// and b.
+func (s StringSet) SymmetricDifference(b StringSet) StringSet {
+	result, i, j := emptyStringSet, 0, 0
+	for i < len(s) || j < len(b) {
+		switch {
+		case j >= len(b) || (i < len(s) && s[i] < b[j]):
+			result = append(result, s[i])
+			i++
+		case i >= len(s) || s[i] > b[j]:
+			result = append(result, b[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Map returns a new set with f applied to every string in s. As f needn't
+// preserve order or uniqueness, the result is re-sorted and deduplicated.
+func (s StringSet) Map(f func(string) string) StringSet {
+	if len(s) == 0 {
+		return emptyStringSet
+	}
+	result := make([]string, len(s))
+	for i, str := range s {
+		result[i] = f(str)
+	}
+	return MakeStringSet(result...)
+}
+
+// Filter returns a new set of the strings in s for which keep returns true.
+// The result is never nil, even if nothing is kept.
+func (s StringSet) Filter(keep func(string) bool) StringSet {
+	result := StringSet{}
+	for _, str := range s {
+		if keep(str) {
+			result = append(result, str)
+		}
+	}
+	return result
+}
+
+// Count returns the number of strings in s for which pred returns true.
+func (s StringSet) Count(pred func(string) bool) int {
+	n := 0
+	for _, str := range s {
+		if pred(str) {
+			n++
+		}
+	}
+	return n
+}
+
+// ForEach executes f for each string in the set, in sorted order.
+func (s StringSet) ForEach(f func(string)) {
+	for _, str := range s {
+		f(str)
+	}
+}
+
 // Add adds the strings to the StringSet. Add is the only valid way to grow a
 // StringSet. Add returns the StringSet to enable chaining.
 func (s StringSet) Add(strs ...string) StringSet {
@@ -67,6 +162,12 @@ func (s StringSet) Add(strs ...string) StringSet {
 	return s
 }
 
+// Remove returns a new StringSet without the given strings, leaving s
+// untouched.
+func (s StringSet) Remove(strs ...string) StringSet {
+	return s.Difference(MakeStringSet(strs...))
+}
+
 // Merge combines the two StringSets and returns a new result.
 func (s StringSet) Merge(other StringSet) StringSet {
 	switch {
@@ -97,3 +198,112 @@ func (s StringSet) Merge(other StringSet) StringSet {
 		}
 	}
 }
+
+// Union merges s with all of others. Rather than allocating a result for
+// each pairwise Merge, it merges back and forth between two buffers. As with
+// Merge, if only one of the sets is non-empty it is returned as is.
+func (s StringSet) Union(others ...StringSet) StringSet {
+	var (
+		sets  = make([]StringSet, 0, len(others)+1)
+		total = 0
+	)
+	for _, set := range append([]StringSet{s}, others...) {
+		if len(set) > 0 {
+			sets = append(sets, set)
+			total += len(set)
+		}
+	}
+	switch len(sets) {
+	case 0:
+		return s
+	case 1:
+		return sets[0]
+	}
+	var (
+		buf         = make(StringSet, 2*total)
+		front, back = buf[:0:total], buf[total:total]
+		result      = sets[0]
+	)
+	for _, set := range sets[1:] {
+		result = mergeInto(front[:0], result, set)
+		front, back = back, front
+	}
+	return result[:len(result):len(result)]
+}
+
+// mergeInto appends the union of a and b to dst, which mustn't overlap them.
+func mergeInto(dst, a, b StringSet) StringSet {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			dst = append(dst, a[i])
+			i++
+		case a[i] > b[j]:
+			dst = append(dst, b[j])
+			j++
+		default:
+			dst = append(dst, a[i])
+			i++
+			j++
+		}
+	}
+	dst = append(dst, a[i:]...)
+	return append(dst, b[j:]...)
+}
+
+// Equal returns true if s and other contain the same strings. A nil set is
+// equal to an empty one.
+func (s StringSet) Equal(other StringSet) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for i := range s {
+		if s[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubsetOf returns true if every string in s is also in other. The empty
+// set is a subset of every set.
+func (s StringSet) IsSubsetOf(other StringSet) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	j := 0
+	for _, str := range s {
+		for j < len(other) && other[j] < str {
+			j++
+		}
+		if j >= len(other) || other[j] != str {
+			return false
+		}
+		j++
+	}
+	return true
+}
+
+// IsSupersetOf returns true if every string in other is also in s.
+func (s StringSet) IsSupersetOf(other StringSet) bool {
+	return other.IsSubsetOf(s)
+}
+
+// ToSortedSlice returns the strings in s as a fresh sorted slice, which the
+// caller is free to modify. It never returns nil.
+func (s StringSet) ToSortedSlice() []string {
+	result := make([]string, len(s))
+	copy(result, s)
+	return result
+}
+
+// less returns true if s sorts lexicographically before other.
+func (s StringSet) less(other StringSet) bool {
+	for i := 0; i < len(s) && i < len(other); i++ {
+		if s[i] != other[i] {
+			return s[i] < other[i]
+		}
+	}
+	return len(s) < len(other)
+}
@@ -1,9 +1,12 @@
 package report_test
 
 import (
+	"fmt"
 	"testing"
 
+	"github.com/weaveworks/common/test"
 	"github.com/weaveworks/scope/report"
+	"github.com/weaveworks/scope/test/reflect"
 )
 
 func TestStringSetContains(t *testing.T) {
@@ -17,6 +20,11 @@ func TestStringSetContains(t *testing.T) {
 		{[]string{"a"}, "foo", false},
 		{[]string{"a", "foo"}, "foo", true},
 		{[]string{"foo", "b"}, "foo", true},
+		{[]string{"a", "b", "c"}, "a", true},
+		{[]string{"a", "b", "c"}, "c", true},
+		{[]string{"a", "b", "c"}, "d", false},
+		{[]string{"a", "c"}, "b", false},
+		{[]string{"b", "c"}, "a", false},
 	} {
 		have := report.MakeStringSet(testcase.contents...).Contains(testcase.target)
 		if testcase.want != have {
@@ -24,3 +32,355 @@ func TestStringSetContains(t *testing.T) {
 		}
 	}
 }
+
+func TestStringSetIntersectionDifference(t *testing.T) {
+	for _, testcase := range []struct {
+		a, b             []string
+		wantIntersection []string
+		wantDifference   []string
+	}{
+		{nil, nil, nil, nil},
+		{nil, []string{"a"}, nil, nil},
+		{[]string{"a"}, nil, nil, []string{"a"}},
+		{[]string{"a", "b"}, []string{"c", "d"}, nil, []string{"a", "b"}},
+		{[]string{"a", "b", "c"}, []string{"b", "c", "d"}, []string{"b", "c"}, []string{"a"}},
+		{[]string{"a", "b", "c"}, []string{"a", "b", "c"}, []string{"a", "b", "c"}, nil},
+	} {
+		a, b := report.MakeStringSet(testcase.a...), report.MakeStringSet(testcase.b...)
+		want, have := report.MakeStringSet(testcase.wantIntersection...), a.Intersection(b)
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%v.Intersection(%v): %s", a, b, test.Diff(want, have))
+		}
+		want, have = report.MakeStringSet(testcase.wantDifference...), a.Difference(b)
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%v.Difference(%v): %s", a, b, test.Diff(want, have))
+		}
+	}
+}
+
+func TestStringSetForEach(t *testing.T) {
+	report.StringSet(nil).ForEach(func(string) {
+		t.Error("ForEach on a nil set should not call f")
+	})
+
+	want := []string{"a", "b", "c", "d"}
+	have := []string{}
+	report.MakeStringSet("d", "b", "a", "c").ForEach(func(s string) {
+		have = append(have, s)
+	})
+	if !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+}
+
+func makeBenchmarkStringSet(n int) report.StringSet {
+	strs := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		strs = append(strs, fmt.Sprint(i))
+	}
+	return report.MakeStringSet(strs...)
+}
+
+var benchmarkCount int
+
+func BenchmarkStringSetForEach(b *testing.B) {
+	set := makeBenchmarkStringSet(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		benchmarkCount = 0
+		set.ForEach(func(string) { benchmarkCount++ })
+	}
+}
+
+func BenchmarkStringSetSliceIteration(b *testing.B) {
+	set := makeBenchmarkStringSet(1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		benchmarkCount = 0
+		strs := make([]string, len(set))
+		copy(strs, set)
+		for range strs {
+			benchmarkCount++
+		}
+	}
+}
+
+func TestStringSetEqual(t *testing.T) {
+	for _, testcase := range []struct {
+		a, b report.StringSet
+		want bool
+	}{
+		{nil, nil, true},
+		{nil, report.StringSet{}, true},
+		{report.MakeStringSet("a", "b"), report.MakeStringSet("b", "a"), true},
+		{report.MakeStringSet("a", "b"), report.MakeStringSet("a", "c"), false},
+		{report.MakeStringSet("a", "b"), report.MakeStringSet("a"), false},
+		{nil, report.MakeStringSet("a"), false},
+	} {
+		if have := testcase.a.Equal(testcase.b); testcase.want != have {
+			t.Errorf("%v.Equal(%v): want %v, have %v", testcase.a, testcase.b, testcase.want, have)
+		}
+		if have := testcase.b.Equal(testcase.a); testcase.want != have {
+			t.Errorf("%v.Equal(%v): want %v, have %v", testcase.b, testcase.a, testcase.want, have)
+		}
+	}
+}
+
+func TestStringSetRemove(t *testing.T) {
+	for _, testcase := range []struct {
+		set    []string
+		remove []string
+		want   []string
+	}{
+		{nil, []string{"a"}, nil},
+		{[]string{"a", "b", "c"}, nil, []string{"a", "b", "c"}},
+		{[]string{"a", "b", "c"}, []string{"b"}, []string{"a", "c"}},
+		{[]string{"a", "b", "c"}, []string{"c", "a", "z"}, []string{"b"}},
+		{[]string{"a", "b", "c"}, []string{"a", "b", "c"}, nil},
+	} {
+		set := report.MakeStringSet(testcase.set...)
+		original := report.MakeStringSet(testcase.set...)
+		want, have := report.MakeStringSet(testcase.want...), set.Remove(testcase.remove...)
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%v.Remove(%v): %s", set, testcase.remove, test.Diff(want, have))
+		}
+		if !reflect.DeepEqual(original, set) {
+			t.Errorf("%v.Remove(%v): modified the original input", set, testcase.remove)
+		}
+	}
+}
+
+func TestStringSetToSortedSlice(t *testing.T) {
+	if have := report.StringSet(nil).ToSortedSlice(); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty slice, got %#v", have)
+	}
+
+	set := report.MakeStringSet("c", "a", "b")
+	have := set.ToSortedSlice()
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(want, have) {
+		t.Error(test.Diff(want, have))
+	}
+	have[0] = "z"
+	if want := report.MakeStringSet("a", "b", "c"); !reflect.DeepEqual(want, set) {
+		t.Errorf("modifying the slice changed the set: %s", test.Diff(want, set))
+	}
+}
+
+func TestStringSetIsSubsetOf(t *testing.T) {
+	for _, testcase := range []struct {
+		a, b             []string
+		subset, superset bool
+	}{
+		{nil, nil, true, true},
+		{nil, []string{"a"}, true, false},
+		{[]string{"a", "b"}, []string{"a", "b"}, true, true},
+		{[]string{"b"}, []string{"a", "b", "c"}, true, false},
+		{[]string{"a", "c"}, []string{"a", "b", "c"}, true, false},
+		{[]string{"a", "b", "c"}, []string{"a", "c"}, false, true},
+		{[]string{"a", "d"}, []string{"a", "b", "c"}, false, false},
+		{[]string{"a"}, []string{"b"}, false, false},
+	} {
+		a, b := report.MakeStringSet(testcase.a...), report.MakeStringSet(testcase.b...)
+		if have := a.IsSubsetOf(b); have != testcase.subset {
+			t.Errorf("%v.IsSubsetOf(%v): want %v, have %v", a, b, testcase.subset, have)
+		}
+		if have := a.IsSupersetOf(b); have != testcase.superset {
+			t.Errorf("%v.IsSupersetOf(%v): want %v, have %v", a, b, testcase.superset, have)
+		}
+	}
+}
+
+func TestStringSetSymmetricDifference(t *testing.T) {
+	for _, testcase := range []struct {
+		a, b, want []string
+	}{
+		{nil, nil, nil},
+		{[]string{"a"}, nil, []string{"a"}},
+		{nil, []string{"a"}, []string{"a"}},
+		{[]string{"a", "c"}, []string{"b", "d"}, []string{"a", "b", "c", "d"}},
+		{[]string{"a", "b"}, []string{"a", "b"}, nil},
+		{[]string{"a", "b", "c"}, []string{"b", "c", "d"}, []string{"a", "d"}},
+	} {
+		a, b := report.MakeStringSet(testcase.a...), report.MakeStringSet(testcase.b...)
+		want := report.MakeStringSet(testcase.want...)
+		if have := a.SymmetricDifference(b); !reflect.DeepEqual(want, have) {
+			t.Errorf("%v.SymmetricDifference(%v): %s", a, b, test.Diff(want, have))
+		}
+		if have := b.SymmetricDifference(a); !reflect.DeepEqual(want, have) {
+			t.Errorf("%v.SymmetricDifference(%v): %s", b, a, test.Diff(want, have))
+		}
+	}
+	a, b := report.MakeStringSet("a", "c"), report.MakeStringSet("b", "d")
+	if want, have := a.Merge(b), a.SymmetricDifference(b); !reflect.DeepEqual(want, have) {
+		t.Errorf("disjoint sets: %s", test.Diff(want, have))
+	}
+}
+
+func TestStringSetMap(t *testing.T) {
+	set := report.MakeStringSet("a", "b", "c")
+	for _, testcase := range []struct {
+		name string
+		f    func(string) string
+		want report.StringSet
+	}{
+		{"identity", func(s string) string { return s }, set},
+		{"prefix", func(s string) string { return "container/" + s }, report.MakeStringSet("container/a", "container/b", "container/c")},
+		{"reorder", func(s string) string { return map[string]string{"a": "z", "b": "y", "c": "x"}[s] }, report.MakeStringSet("x", "y", "z")},
+		{"collapse", func(s string) string {
+			if s == "b" {
+				return "a"
+			}
+			return s
+		}, report.MakeStringSet("a", "c")},
+	} {
+		if have := set.Map(testcase.f); !reflect.DeepEqual(testcase.want, have) {
+			t.Errorf("%s: %s", testcase.name, test.Diff(testcase.want, have))
+		}
+	}
+	if have := report.StringSet(nil).Map(func(s string) string { return s }); have != nil {
+		t.Errorf("expected nil, got %v", have)
+	}
+}
+
+func TestStringSetIsEmptyFirst(t *testing.T) {
+	for _, testcase := range []struct {
+		set   report.StringSet
+		empty bool
+		first string
+	}{
+		{nil, true, ""},
+		{report.StringSet{}, true, ""},
+		{report.MakeStringSet("b", "a", "c"), false, "a"},
+	} {
+		if have := testcase.set.IsEmpty(); have != testcase.empty {
+			t.Errorf("%#v.IsEmpty(): want %v, have %v", testcase.set, testcase.empty, have)
+		}
+		first, ok := testcase.set.First()
+		if first != testcase.first || ok == testcase.empty {
+			t.Errorf("%#v.First(): want %q, %v, have %q, %v", testcase.set, testcase.first, !testcase.empty, first, ok)
+		}
+	}
+}
+
+func TestStringSetFilter(t *testing.T) {
+	set := report.MakeStringSet("a", "b", "c", "d")
+	for _, testcase := range []struct {
+		name string
+		keep func(string) bool
+		want report.StringSet
+	}{
+		{"all", func(string) bool { return true }, set},
+		{"none", func(string) bool { return false }, report.StringSet{}},
+		{"some", report.MakeStringSet("b", "d", "z").Contains, report.MakeStringSet("b", "d")},
+	} {
+		have := set.Filter(testcase.keep)
+		if have == nil || !reflect.DeepEqual(testcase.want, have) {
+			t.Errorf("%s: %s", testcase.name, test.Diff(testcase.want, have))
+		}
+	}
+	if have := report.StringSet(nil).Filter(func(string) bool { return true }); have == nil || len(have) != 0 {
+		t.Errorf("expected non-nil empty set, got %#v", have)
+	}
+	set.Filter(func(string) bool { return false })
+	if want := report.MakeStringSet("a", "b", "c", "d"); !reflect.DeepEqual(want, set) {
+		t.Errorf("Filter modified the original set: %s", test.Diff(want, set))
+	}
+}
+
+func TestStringSetUnion(t *testing.T) {
+	for _, testcase := range []struct {
+		name string
+		sets [][]string
+		want []string
+	}{
+		{"none", nil, nil},
+		{"one", [][]string{{"b", "a"}}, []string{"a", "b"}},
+		{"empty", [][]string{nil, {}, nil}, nil},
+		{"disjoint", [][]string{{"a", "d"}, {"b", "e"}, {"c", "f"}}, []string{"a", "b", "c", "d", "e", "f"}},
+		{"overlapping", [][]string{{"a", "b", "c"}, {"b", "c", "d"}, {"a", "d", "e"}}, []string{"a", "b", "c", "d", "e"}},
+		{"some empty", [][]string{nil, {"b"}, {}, {"a", "b"}}, []string{"a", "b"}},
+	} {
+		sets := []report.StringSet{}
+		for _, strs := range testcase.sets {
+			sets = append(sets, report.MakeStringSet(strs...))
+		}
+		var have report.StringSet
+		if len(sets) == 0 {
+			have = report.StringSet(nil).Union()
+		} else {
+			have = sets[0].Union(sets[1:]...)
+		}
+		want := report.MakeStringSet(testcase.want...)
+		if !reflect.DeepEqual(want, have) {
+			t.Errorf("%s: %s", testcase.name, test.Diff(want, have))
+		}
+		chained := report.MakeStringSet()
+		for _, set := range sets {
+			chained = chained.Merge(set)
+		}
+		if !chained.Equal(have) {
+			t.Errorf("%s: Union %v differs from chained Merge %v", testcase.name, have, chained)
+		}
+	}
+}
+
+func makeBenchmarkStringSets() []report.StringSet {
+	sets := []report.StringSet{}
+	for i := 0; i < 10; i++ {
+		strs := []string{}
+		for j := 0; j < 100; j++ {
+			strs = append(strs, fmt.Sprint((i*37+j*7)%500))
+		}
+		sets = append(sets, report.MakeStringSet(strs...))
+	}
+	return sets
+}
+
+var benchmarkStringSet report.StringSet
+
+func BenchmarkStringSetUnion(b *testing.B) {
+	sets := makeBenchmarkStringSets()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		benchmarkStringSet = sets[0].Union(sets[1:]...)
+	}
+}
+
+func BenchmarkStringSetChainedMerge(b *testing.B) {
+	sets := makeBenchmarkStringSets()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		result := sets[0]
+		for _, set := range sets[1:] {
+			result = result.Merge(set)
+		}
+		benchmarkStringSet = result
+	}
+}
+
+func TestStringSetCount(t *testing.T) {
+	set := report.MakeStringSet("delete", "restart", "start", "stop")
+	for _, testcase := range []struct {
+		name string
+		set  report.StringSet
+		pred func(string) bool
+		want int
+	}{
+		{"all", set, func(string) bool { return true }, 4},
+		{"none", set, func(string) bool { return false }, 0},
+		{"some", set, report.MakeStringSet("delete", "stop", "kill").Contains, 2},
+		{"nil", nil, func(string) bool { return true }, 0},
+	} {
+		if have := testcase.set.Count(testcase.pred); have != testcase.want {
+			t.Errorf("%s: want %d, have %d", testcase.name, testcase.want, have)
+		}
+	}
+}
@@ -24,7 +24,7 @@ type Topology struct {
 func MakeTopology() Topology {
 	return Topology{
 		Nodes:    map[string]Node{},
-		Controls: Controls{},
+		Controls: MakeControls(),
 	}
 }
 
@@ -137,7 +137,8 @@ func (t Topology) Copy() Topology {
 }
 
 // Merge merges the other object into this one, and returns the result object.
-// The original is not modified.
+// The original is not modified, but the result's Controls may be t's own (see
+// Controls.Merge), so copy them before adding controls to the result.
 func (t Topology) Merge(other Topology) Topology {
 	shape := t.Shape
 	if shape == "" {